@@ -0,0 +1,311 @@
+package goutils
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// =================================================
+
+// Cache query result cache, every entry can be tagged so it is invalidated together, e.g. by table name.
+// Version is changed by every InvalidateTags of the tags, so SetIfVersion does not store value
+// which is loaded before invalidation
+type Cache interface {
+	Get(key string) (value interface{}, ok bool)
+	Set(key string, value interface{}, ttl time.Duration, tags ...string)
+	SetIfVersion(key string, value interface{}, ttl time.Duration, version uint64, tags ...string) bool
+	Version(tags ...string) uint64
+	Delete(key string)
+	InvalidateTags(tags ...string)
+}
+
+type memoryCache struct {
+	mu          sync.Mutex
+	capacity    int
+	ttl         time.Duration
+	items       map[string]*list.Element
+	order       *list.List
+	tags        map[string]map[string]struct{}
+	generations map[string]uint64
+	now         func() time.Time
+}
+
+type cacheItem struct {
+	key       string
+	value     interface{}
+	expiredAt time.Time
+	tags      []string
+}
+
+// NewMemoryCache create in memory cache, least recently used entry is evicted when capacity is reached,
+// ttl is used when ttl of Set is zero, capacity or ttl zero means unlimited
+func NewMemoryCache(capacity int, ttl time.Duration) Cache {
+	return &memoryCache{
+		capacity:    capacity,
+		ttl:         ttl,
+		items:       make(map[string]*list.Element),
+		order:       list.New(),
+		tags:        make(map[string]map[string]struct{}),
+		generations: make(map[string]uint64),
+		now:         time.Now,
+	}
+}
+
+// Get get cached value, expired entry is removed
+func (c *memoryCache) Get(key string) (value interface{}, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	item := element.Value.(*cacheItem)
+	if !item.expiredAt.IsZero() && !c.now().Before(item.expiredAt) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return item.value, true
+}
+
+// Set set cached value with tags
+func (c *memoryCache) Set(key string, value interface{}, ttl time.Duration, tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(key, value, ttl, tags)
+}
+
+// SetIfVersion set cached value with tags when version of tags is unchanged,
+// false is returned when one of tags is invalidated after version is read
+func (c *memoryCache) SetIfVersion(key string, value interface{}, ttl time.Duration, version uint64, tags ...string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.version(tags) != version {
+		return false
+	}
+
+	c.set(key, value, ttl, tags)
+	return true
+}
+
+// Version version of tags, it is changed by every InvalidateTags of one of tags
+func (c *memoryCache) Version(tags ...string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.version(tags)
+}
+
+func (c *memoryCache) version(tags []string) (version uint64) {
+	for _, tag := range tags {
+		version += c.generations[tag]
+	}
+	return
+}
+
+func (c *memoryCache) set(key string, value interface{}, ttl time.Duration, tags []string) {
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+
+	if ttl == 0 {
+		ttl = c.ttl
+	}
+
+	item := &cacheItem{
+		key:   key,
+		value: value,
+		tags:  tags,
+	}
+	if ttl > 0 {
+		item.expiredAt = c.now().Add(ttl)
+	}
+
+	c.items[key] = c.order.PushFront(item)
+	for _, tag := range tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = make(map[string]struct{})
+		}
+		c.tags[tag][key] = struct{}{}
+	}
+
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// Delete delete cached value
+func (c *memoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+}
+
+// InvalidateTags delete every cached value which has one of tags
+func (c *memoryCache) InvalidateTags(tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tag := range tags {
+		c.generations[tag]++
+		for key := range c.tags[tag] {
+			if element, ok := c.items[key]; ok {
+				c.remove(element)
+			}
+		}
+		delete(c.tags, tag)
+	}
+}
+
+func (c *memoryCache) remove(element *list.Element) {
+	item := c.order.Remove(element).(*cacheItem)
+	delete(c.items, item.key)
+	for _, tag := range item.tags {
+		delete(c.tags[tag], item.key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
+
+// =================================================
+
+// CacheQuery read through cache of query result, the key is GetCacheKey of query and
+// the entry is tagged with every table of query, so InvalidateTags(tablename) evicts it after write.
+// Result is not cached when one of tables is invalidated while loader is running
+func CacheQuery[T any](cache Cache, query QueryBuilderInteractor, tablename string, aliases string, ttl time.Duration, loader func(query string, values []interface{}) (T, error)) (result T, err error) {
+	key, err := query.GetCacheKey(tablename, aliases)
+	if err != nil {
+		return
+	}
+
+	if value, ok := cache.Get(key); ok {
+		if result, ok = value.(T); ok {
+			return
+		}
+	}
+
+	sql, values, err := query.GetQuery(tablename, aliases)
+	if err != nil {
+		return
+	}
+
+	tags := queryTables(query, tablename)
+	version := cache.Version(tags...)
+
+	result, err = loader(sql, values)
+	if err != nil {
+		return
+	}
+
+	cache.SetIfVersion(key, result, ttl, version, tags...)
+	return
+}
+
+// queryTables table names which are used by query, including join, from, with, set operation and where subqueries
+func queryTables(query QueryBuilderInteractor, tablename string) []string {
+	tables := make(map[string]struct{})
+	if tablename != "" {
+		tables[tablename] = struct{}{}
+	}
+
+	if q, ok := query.(*queryBuilder); ok {
+		q.collectTables(tables)
+	}
+
+	result := make([]string, 0, len(tables))
+	for table := range tables {
+		result = append(result, table)
+	}
+	return result
+}
+
+func (q *queryBuilder) collectTables(tables map[string]struct{}) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.from != nil {
+		collectSubqueryTables(*q.from, tables)
+	}
+
+	if q.join != nil {
+		for _, val := range *q.join {
+			tables[val.tableName] = struct{}{}
+		}
+	}
+
+	if q.with != nil {
+		for _, val := range *q.with {
+			collectSubqueryTables(val.anchor, tables)
+			if val.iterate != nil {
+				collectSubqueryTables(*val.iterate, tables)
+			}
+		}
+	}
+
+	if q.compound != nil {
+		for _, val := range *q.compound {
+			collectSubqueryTables(val.subquery, tables)
+		}
+	}
+
+	if q.selection != nil {
+		for _, val := range *q.selection {
+			collectValueTables(val, tables)
+		}
+	}
+
+	for _, listWhere := range []*[]map[string]interface{}{q.where, q.having} {
+		if listWhere == nil {
+			continue
+		}
+
+		for _, where := range *listWhere {
+			collectValueTables(where, tables)
+		}
+	}
+}
+
+func collectSubqueryTables(s subquery, tables map[string]struct{}) {
+	if s.tablename != "" {
+		tables[s.tablename] = struct{}{}
+	}
+
+	if q, ok := s.query.(*queryBuilder); ok {
+		q.collectTables(tables)
+	}
+}
+
+func collectValueTables(value interface{}, tables map[string]struct{}) {
+	switch val := value.(type) {
+	case subquery:
+		collectSubqueryTables(val, tables)
+	case aliasedExpression:
+		collectValueTables(val.expression, tables)
+	case comparison:
+		collectValueTables(val.value, tables)
+	case logical:
+		for _, condition := range val.conditions {
+			collectValueTables(condition, tables)
+		}
+	case negation:
+		collectValueTables(val.condition, tables)
+	case map[string]interface{}:
+		for _, item := range val {
+			collectValueTables(item, tables)
+		}
+	case []map[string]interface{}:
+		for _, item := range val {
+			collectValueTables(item, tables)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package goutils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"reflect"
+	"strconv"
+)
+
+// =================================================
+
+// WithCacheNamespace set namespace prefix of cache key, e.g. service or version name
+func WithCacheNamespace(namespace string) QueryBuilderOption {
+	return func(option *builderOption) {
+		option.namespace = namespace
+	}
+}
+
+// GetCacheKey canonical cache key of query, a sha256 hash of dialect, parsed query and values,
+// prefixed with namespace when it is set. Unlike GetKey, every part of builder is included in the key
+func (q *queryBuilder) GetCacheKey(tablename string, aliases string) (string, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	query, values, err := q.parseQuery(q.option.dialect, tablename, aliases)
+	if err != nil {
+		return "", err
+	}
+
+	return cacheKey(q.option.namespace, q.option.dialect, query, values), nil
+}
+
+// cacheKey hash query and values, every part is length prefixed so the boundary of parts is not ambiguous
+func cacheKey(namespace string, d dialect, query string, values []interface{}) string {
+	h := sha256.New()
+	writeKeyPart(h, d.name)
+	writeKeyPart(h, query)
+	for _, val := range values {
+		writeKeyPart(h, formatKeyValue(val))
+	}
+
+	key := hex.EncodeToString(h.Sum(nil))
+	if namespace != "" {
+		key = namespace + ":" + key
+	}
+
+	return key
+}
+
+func writeKeyPart(h hash.Hash, part string) {
+	h.Write([]byte(strconv.Itoa(len(part))))
+	h.Write([]byte{':'})
+	h.Write([]byte(part))
+}
+
+// formatKeyValue format value with its type, pointer is dereferenced so the key does not depend on address
+func formatKeyValue(value interface{}) string {
+	reflectValue := reflect.ValueOf(value)
+	for reflectValue.Kind() == reflect.Ptr && !reflectValue.IsNil() {
+		reflectValue = reflectValue.Elem()
+	}
+
+	if !reflectValue.IsValid() {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("%T=%#v", reflectValue.Interface(), reflectValue.Interface())
+}
@@ -0,0 +1,82 @@
+package goutils
+
+import (
+	"strings"
+	"testing"
+)
+
+func getCacheKey(t *testing.T, tablename string, build func(query QueryBuilderInteractor), opts ...QueryBuilderOption) string {
+	t.Helper()
+	query := NewQueryBuilder(append([]QueryBuilderOption{WithDialect(PostgreSQL)}, opts...)...)
+	build(query)
+
+	key, err := query.GetCacheKey(tablename, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestGetCacheKey(t *testing.T) {
+	base := func(query QueryBuilderInteractor) {
+		query.AddSelection("id")
+		query.AddWhere("status", "", "paid")
+	}
+
+	key := getCacheKey(t, "orders", base)
+	if same := getCacheKey(t, "orders", base); same != key {
+		t.Errorf("expected identical key of same query, got %q and %q", key, same)
+	}
+
+	tests := map[string]string{
+		"table": getCacheKey(t, "archived_orders", base),
+		"join": getCacheKey(t, "orders", func(query QueryBuilderInteractor) {
+			base(query)
+			query.AddJoin(InnerJoin, "users", "u", "u.id = orders.user_id")
+		}),
+		"selection": getCacheKey(t, "orders", func(query QueryBuilderInteractor) {
+			base(query)
+			query.AddSelection("amount")
+		}),
+		"value type": getCacheKey(t, "orders", func(query QueryBuilderInteractor) {
+			query.AddSelection("id")
+			query.AddWhere("status", "", []byte("paid"))
+		}),
+		"dialect": getCacheKey(t, "orders", base, WithDialect(MySQL)),
+	}
+
+	for name, val := range tests {
+		if val == key {
+			t.Errorf("%s: expected different key", name)
+		}
+	}
+}
+
+func TestGetCacheKeyValueBoundary(t *testing.T) {
+	first := getCacheKey(t, "orders", func(query QueryBuilderInteractor) {
+		query.AddWhereExpression(Raw("code = ? || ?", "a-b", "c"))
+	})
+	second := getCacheKey(t, "orders", func(query QueryBuilderInteractor) {
+		query.AddWhereExpression(Raw("code = ? || ?", "a", "b-c"))
+	})
+
+	if first == second {
+		t.Error("expected values containing - not to collide")
+	}
+}
+
+func TestGetCacheKeyNamespace(t *testing.T) {
+	build := func(query QueryBuilderInteractor) {
+		query.AddWhere("id", "", 1)
+	}
+
+	key := getCacheKey(t, "orders", build)
+	namespaced := getCacheKey(t, "orders", build, WithCacheNamespace("billing:v2"))
+	if namespaced != "billing:v2:"+key {
+		t.Errorf("expected namespace prefix of %q, got %q", key, namespaced)
+	}
+
+	if strings.Contains(key, ":") {
+		t.Errorf("expected key without namespace, got %q", key)
+	}
+}
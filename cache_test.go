@@ -0,0 +1,145 @@
+package goutils
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestMemoryCache(capacity int, ttl time.Duration) (*memoryCache, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewMemoryCache(capacity, ttl).(*memoryCache)
+	cache.now = func() time.Time {
+		return now
+	}
+	return cache, &now
+}
+
+func TestMemoryCacheLRU(t *testing.T) {
+	cache, _ := newTestMemoryCache(2, 0)
+	cache.Set("a", 1, 0)
+	cache.Set("b", 2, 0)
+
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+
+	cache.Set("c", 3, 0)
+	if _, ok := cache.Get("b"); ok {
+		t.Error("expected least recently used b to be evicted")
+	}
+
+	for key, expected := range map[string]int{"a": 1, "c": 3} {
+		if value, ok := cache.Get(key); !ok || value != expected {
+			t.Errorf("%s: expected %d, got %v %v", key, expected, value, ok)
+		}
+	}
+
+	cache.Delete("a")
+	if _, ok := cache.Get("a"); ok {
+		t.Error("expected a to be deleted")
+	}
+}
+
+func TestMemoryCacheTTL(t *testing.T) {
+	cache, now := newTestMemoryCache(0, time.Minute)
+	cache.Set("default", 1, 0)
+	cache.Set("custom", 2, time.Hour)
+
+	*now = now.Add(time.Minute)
+	if _, ok := cache.Get("default"); ok {
+		t.Error("expected default ttl entry to be expired")
+	}
+
+	if _, ok := cache.Get("custom"); !ok {
+		t.Error("expected custom ttl entry to be cached")
+	}
+
+	*now = now.Add(time.Hour)
+	if _, ok := cache.Get("custom"); ok {
+		t.Error("expected custom ttl entry to be expired")
+	}
+
+	if len(cache.items) != 0 || len(cache.tags) != 0 {
+		t.Errorf("expected expired entries to be removed, got %d items", len(cache.items))
+	}
+}
+
+func TestMemoryCacheInvalidateTags(t *testing.T) {
+	cache, _ := newTestMemoryCache(0, 0)
+	cache.Set("orders", 1, 0, "orders")
+	cache.Set("orders-users", 2, 0, "orders", "users")
+	cache.Set("users", 3, 0, "users")
+
+	version := cache.Version("users")
+	cache.InvalidateTags("orders")
+
+	for _, key := range []string{"orders", "orders-users"} {
+		if _, ok := cache.Get(key); ok {
+			t.Errorf("expected %s to be invalidated", key)
+		}
+	}
+
+	if _, ok := cache.Get("users"); !ok {
+		t.Error("expected users to be cached")
+	}
+
+	if !cache.SetIfVersion("users-only", 4, 0, version, "users") {
+		t.Error("expected version of users to be unchanged")
+	}
+
+	if cache.SetIfVersion("stale", 5, 0, version, "users", "orders") {
+		t.Error("expected version of orders to be changed")
+	}
+}
+
+func TestCacheQuery(t *testing.T) {
+	cache, _ := newTestMemoryCache(0, 0)
+	query := NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddJoin(InnerJoin, "users", "u", "u.id = o.user_id")
+	query.AddWhere("o.status", "", "paid")
+
+	var calls int
+	loader := func(sql string, values []interface{}) ([]int, error) {
+		calls++
+		if expected := `SELECT * FROM "orders" "o" INNER JOIN "users" "u" ON "u"."id" = "o"."user_id" WHERE "o"."status" = $1`; sql != expected {
+			t.Errorf("expected query %q, got %q", expected, sql)
+		}
+		return []int{calls}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		result, err := CacheQuery(cache, query, "orders", "o", 0, loader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(result) != 1 || result[0] != 1 || calls != 1 {
+			t.Errorf("expected cached result, got %v after %d calls", result, calls)
+		}
+	}
+
+	cache.InvalidateTags("users")
+	if result, _ := CacheQuery(cache, query, "orders", "o", 0, loader); result[0] != 2 {
+		t.Errorf("expected result to be loaded after join table is invalidated, got %v", result)
+	}
+}
+
+func TestCacheQueryInvalidatedWhileLoading(t *testing.T) {
+	cache, _ := newTestMemoryCache(0, 0)
+	query := NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddWhere("status", "", "paid")
+
+	result, err := CacheQuery(cache, query, "orders", "", 0, func(string, []interface{}) (int, error) {
+		// write to orders is committed while stale result is loaded
+		cache.InvalidateTags("orders")
+		return 1, nil
+	})
+	if err != nil || result != 1 {
+		t.Fatalf("expected loaded result, got %v %v", result, err)
+	}
+
+	key, _ := query.GetCacheKey("orders", "")
+	if _, ok := cache.Get(key); ok {
+		t.Error("expected stale result not to be cached")
+	}
+}
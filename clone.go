@@ -0,0 +1,185 @@
+package goutils
+
+// =================================================
+
+// Clone deep copy query builder, nested subqueries and where maps are copied too,
+// so the clone can be modified without affecting the original query builder
+func (q *queryBuilder) Clone() QueryBuilderInteractor {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return q.clone()
+}
+
+func (q *queryBuilder) clone() *queryBuilder {
+	clone := &queryBuilder{
+		pagination: q.pagination,
+		cursor:     q.cursor,
+		key:        q.key,
+		option:     q.option,
+		errs:       append([]error(nil), q.errs...),
+	}
+
+	if q.selection != nil {
+		selection := make([]expression, 0, len(*q.selection))
+		for _, val := range *q.selection {
+			selection = append(selection, cloneValue(val).(expression))
+		}
+		clone.selection = &selection
+	}
+
+	if q.from != nil {
+		from := cloneSubquery(*q.from)
+		clone.from = &from
+	}
+
+	if q.with != nil {
+		with := make([]commonTable, 0, len(*q.with))
+		for _, val := range *q.with {
+			val.columns = append([]string{}, val.columns...)
+			val.anchor = cloneSubquery(val.anchor)
+			if val.iterate != nil {
+				iterate := cloneSubquery(*val.iterate)
+				val.iterate = &iterate
+			}
+			with = append(with, val)
+		}
+		clone.with = &with
+	}
+
+	if q.compound != nil {
+		compound := make([]compound, 0, len(*q.compound))
+		for _, val := range *q.compound {
+			val.subquery = cloneSubquery(val.subquery)
+			compound = append(compound, val)
+		}
+		clone.compound = &compound
+	}
+
+	if q.sort != nil {
+		sort := append([]sortItem{}, *q.sort...)
+		clone.sort = &sort
+	}
+
+	if q.join != nil {
+		join := append([]join{}, *q.join...)
+		clone.join = &join
+	}
+
+	if q.group != nil {
+		group := append([]string{}, *q.group...)
+		clone.group = &group
+	}
+
+	clone.where = cloneWhereList(q.where)
+	clone.having = cloneWhereList(q.having)
+	return clone
+}
+
+func cloneWhereList(listWhere *[]map[string]interface{}) *[]map[string]interface{} {
+	if listWhere == nil {
+		return nil
+	}
+
+	arrWhere := make([]map[string]interface{}, 0, len(*listWhere))
+	for _, where := range *listWhere {
+		arrWhere = append(arrWhere, cloneValue(where).(map[string]interface{}))
+	}
+	return &arrWhere
+}
+
+func cloneSubquery(s subquery) subquery {
+	if builder, ok := s.query.(*queryBuilder); ok {
+		s.query = builder.Clone()
+	}
+	return s
+}
+
+// cloneValue deep copy where map, list and expression which contains subquery
+func cloneValue(value interface{}) interface{} {
+	switch val := value.(type) {
+	case subquery:
+		return cloneSubquery(val)
+	case aliasedExpression:
+		val.expression = cloneValue(val.expression).(expression)
+		return val
+	case comparison:
+		val.value = cloneValue(val.value)
+		return val
+	case logical:
+		conditions := make([]Condition, 0, len(val.conditions))
+		for _, condition := range val.conditions {
+			if condition == nil {
+				conditions = append(conditions, nil)
+				continue
+			}
+			conditions = append(conditions, cloneValue(condition).(Condition))
+		}
+		val.conditions = conditions
+		return val
+	case negation:
+		if val.condition != nil {
+			val.condition = cloneValue(val.condition).(Condition)
+		}
+		return val
+	case map[string]interface{}:
+		data := make(map[string]interface{}, len(val))
+		for key, item := range val {
+			data[key] = cloneValue(item)
+		}
+		return data
+	case []map[string]interface{}:
+		list := make([]map[string]interface{}, 0, len(val))
+		for _, item := range val {
+			list = append(list, cloneValue(item).(map[string]interface{}))
+		}
+		return list
+	case []interface{}:
+		list := make([]interface{}, 0, len(val))
+		for _, item := range val {
+			list = append(list, cloneValue(item))
+		}
+		return list
+	}
+
+	return value
+}
+
+// =================================================
+
+// QueryScope immutable query builder, With return new scope and the receiver is unchanged,
+// so base scope can be shared across goroutines and extended for every query variant
+type QueryScope struct {
+	query *queryBuilder
+}
+
+// NewQueryScope create immutable scope from copy of query builder
+func NewQueryScope(query QueryBuilderInteractor) QueryScope {
+	return QueryScope{
+		query: query.Clone().(*queryBuilder),
+	}
+}
+
+// With return new scope with the changes of fn applied to copy of query builder
+func (s QueryScope) With(fn func(query QueryBuilderInteractor)) QueryScope {
+	query := s.query.Clone()
+	fn(query)
+	return QueryScope{
+		query: query.(*queryBuilder),
+	}
+}
+
+// Builder return mutable copy of query builder
+func (s QueryScope) Builder() QueryBuilderInteractor {
+	return s.query.Clone()
+}
+
+// GetQuery parse query of scope
+func (s QueryScope) GetQuery(tablename string, aliases string) (query string, values []interface{}, err error) {
+	return s.query.GetQuery(tablename, aliases)
+}
+
+// GetCountQuery parse count query of scope
+func (s QueryScope) GetCountQuery(tablename string, aliases string) (query string, values []interface{}, err error) {
+	return s.query.GetCountQuery(tablename, aliases)
+}
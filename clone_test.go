@@ -0,0 +1,86 @@
+package goutils
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCloneIsIndependent(t *testing.T) {
+	orders := NewQueryBuilder()
+	orders.AddSelection("user_id")
+	orders.AddWhere("status", "", "paid")
+
+	base := NewQueryBuilder(WithDialect(PostgreSQL))
+	base.AddSelection("id")
+	base.AddRawWhere(map[string]interface{}{"OR": map[string]interface{}{"a": 1, "b": 2}})
+	base.AddWhere("id", "in", Subquery(orders, "orders", ""))
+	base.AddSort(DirAsc, "id")
+
+	expected, expectedValues, err := base.GetQuery("users", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clone := base.Clone()
+	clone.AddSelection("name")
+	clone.AddWhere("active", "", true)
+	clone.AddSort(DirDesc, "name")
+	clone.AddJoin(InnerJoin, "roles", "r", "r.id = users.role_id")
+	clone.AddPagination(NewPagination(2, 10))
+
+	// where map and nested subquery of clone are copied too
+	(*clone.(*queryBuilder).where)[0]["OR"].(map[string]interface{})["c"] = 3
+	(*clone.(*queryBuilder).where)[1]["in"].(map[string]interface{})["id"].(subquery).query.AddWhere("amount", "gt", 100)
+
+	res, values, err := base.GetQuery("users", "")
+	assertQuery(t, "base", res, values, err, expected, expectedValues)
+
+	res, values, err = clone.GetQuery("users", "")
+	assertQuery(t, "clone", res, values, err,
+		`SELECT "id", "name" FROM "users" INNER JOIN "roles" "r" ON "r"."id" = "users"."role_id" `+
+			`WHERE (((("a" = $1 OR "b" = $2) OR "c" = $3) AND "id" IN (SELECT "user_id" FROM "orders" WHERE ("status" = $4 AND "amount" > $5))) AND "active" = $6) `+
+			`ORDER BY "id" ASC, "name" DESC LIMIT 10 OFFSET 10`,
+		[]interface{}{1, 2, 3, "paid", 100, true},
+	)
+}
+
+func TestQueryScopeConcurrentWith(t *testing.T) {
+	base := NewQueryBuilder(WithDialect(PostgreSQL))
+	base.AddWhere("tenant_id", "", 1)
+	scope := NewQueryScope(base)
+
+	// base builder is copied, so changing it does not change the scope
+	base.AddWhere("deleted", "", false)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			variant := scope.With(func(query QueryBuilderInteractor) {
+				query.AddWhere("id", "", i)
+				query.AddSort(DirAsc, "id")
+			})
+
+			res, values, err := variant.GetQuery("users", "")
+			assertQuery(t, fmt.Sprintf("variant %d", i), res, values, err,
+				`SELECT * FROM "users" WHERE ("tenant_id" = $1 AND "id" = $2) ORDER BY "id" ASC`,
+				[]interface{}{1, i},
+			)
+
+			if _, _, err := scope.GetCountQuery("users", ""); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	res, values, err := scope.GetQuery("users", "")
+	assertQuery(t, "scope", res, values, err, `SELECT * FROM "users" WHERE "tenant_id" = $1`, []interface{}{1})
+
+	builder := scope.Builder()
+	builder.AddWhere("id", "", 2)
+	res, values, err = scope.GetQuery("users", "")
+	assertQuery(t, "builder", res, values, err, `SELECT * FROM "users" WHERE "tenant_id" = $1`, []interface{}{1})
+}
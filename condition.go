@@ -0,0 +1,217 @@
+package goutils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// =================================================
+
+// Condition typed where condition, built with Eq, Gt, In, Like, Between, IsNull, And, Or, Not and so on
+type Condition interface {
+	expression
+	fmt.Stringer
+}
+
+type comparison struct {
+	attribute string
+	operation string
+	value     interface{}
+}
+
+type logical struct {
+	operator   string
+	conditions []Condition
+}
+
+type negation struct {
+	condition Condition
+}
+
+// Eq attribute = value, IS NULL when value is nil
+func Eq(attribute string, value interface{}) Condition {
+	return comparison{attribute: attribute, operation: "eq", value: value}
+}
+
+// Neq attribute != value, IS NOT NULL when value is nil
+func Neq(attribute string, value interface{}) Condition {
+	return comparison{attribute: attribute, operation: "neq", value: value}
+}
+
+// Gt attribute > value
+func Gt(attribute string, value interface{}) Condition {
+	return comparison{attribute: attribute, operation: "gt", value: value}
+}
+
+// Gte attribute >= value
+func Gte(attribute string, value interface{}) Condition {
+	return comparison{attribute: attribute, operation: "gte", value: value}
+}
+
+// Lt attribute < value
+func Lt(attribute string, value interface{}) Condition {
+	return comparison{attribute: attribute, operation: "lt", value: value}
+}
+
+// Lte attribute <= value
+func Lte(attribute string, value interface{}) Condition {
+	return comparison{attribute: attribute, operation: "lte", value: value}
+}
+
+// In attribute in list of values or subquery
+func In(attribute string, values interface{}) Condition {
+	return comparison{attribute: attribute, operation: "in", value: values}
+}
+
+// NotIn attribute not in list of values or subquery
+func NotIn(attribute string, values interface{}) Condition {
+	return comparison{attribute: attribute, operation: "not_in", value: values}
+}
+
+// Like attribute LIKE pattern
+func Like(attribute string, pattern string) Condition {
+	return comparison{attribute: attribute, operation: "like", value: pattern}
+}
+
+// ILike case insensitive attribute LIKE pattern
+func ILike(attribute string, pattern string) Condition {
+	return comparison{attribute: attribute, operation: "i_like", value: pattern}
+}
+
+// Between attribute BETWEEN from AND to
+func Between(attribute string, from interface{}, to interface{}) Condition {
+	return comparison{attribute: attribute, operation: "between", value: []interface{}{from, to}}
+}
+
+// IsNull attribute IS NULL
+func IsNull(attribute string) Condition {
+	return comparison{attribute: attribute, operation: "is", value: nil}
+}
+
+// IsNotNull attribute IS NOT NULL
+func IsNotNull(attribute string) Condition {
+	return comparison{attribute: attribute, operation: "is_not", value: nil}
+}
+
+// Exists EXISTS (subquery)
+func Exists(subquery subquery) Condition {
+	return comparison{operation: "exists", value: subquery}
+}
+
+// And join conditions with AND
+func And(conditions ...Condition) Condition {
+	return logical{operator: "AND", conditions: conditions}
+}
+
+// Or join conditions with OR
+func Or(conditions ...Condition) Condition {
+	return logical{operator: "OR", conditions: conditions}
+}
+
+// Not negate condition
+func Not(condition Condition) Condition {
+	return negation{condition: condition}
+}
+
+// =================================================
+
+func (c comparison) parse(d dialect) (query string, values []interface{}, err error) {
+	return getOperation(d, c.attribute, c.operation, c.value)
+}
+
+// String condition cache key
+func (c comparison) String() string {
+	return fmt.Sprintf("%s-%s-%v", c.attribute, c.operation, c.value)
+}
+
+func (l logical) parse(d dialect) (query string, values []interface{}, err error) {
+	if len(l.conditions) == 0 {
+		err = newQueryError(ErrInvalidValue, "", l.operator, nil)
+		return
+	}
+
+	for i, condition := range l.conditions {
+		if condition == nil {
+			err = prependPath(newQueryError(ErrInvalidValue, "", l.operator, nil), l.operator, strconv.Itoa(i))
+			return
+		}
+
+		q, v, err := condition.parse(d)
+		if err != nil {
+			return query, values, prependPath(err, l.operator, strconv.Itoa(i))
+		}
+
+		if query == "" {
+			query = q
+		} else {
+			query = fmt.Sprintf("(%s %s %s)", query, l.operator, q)
+		}
+		values = append(values, v...)
+	}
+	return
+}
+
+// String condition cache key
+func (l logical) String() string {
+	conditions := make([]string, 0, len(l.conditions))
+	for _, condition := range l.conditions {
+		conditions = append(conditions, fmt.Sprintf("%v", condition))
+	}
+
+	return fmt.Sprintf("%s(%s)", l.operator, strings.Join(conditions, ","))
+}
+
+func (n negation) parse(d dialect) (query string, values []interface{}, err error) {
+	if n.condition == nil {
+		err = newQueryError(ErrInvalidValue, "", "NOT", nil)
+		return
+	}
+
+	query, values, err = n.condition.parse(d)
+	if err != nil {
+		err = prependPath(err, "NOT")
+		return
+	}
+
+	query = fmt.Sprintf("NOT (%s)", query)
+	return
+}
+
+// String condition cache key
+func (n negation) String() string {
+	return fmt.Sprintf("NOT(%v)", n.condition)
+}
+
+// =================================================
+
+// AddCondition add typed where condition, conditions are joined with AND
+func (q *queryBuilder) AddCondition(condition ...Condition) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, val := range condition {
+		q.addKey(val)
+		q.where = appendWhere(q.where, map[string]interface{}{
+			RawKey: val,
+		})
+	}
+}
+
+// AddCondition add typed where condition, conditions are joined with AND
+func (q *updateBuilder) AddCondition(condition ...Condition) {
+	for _, val := range condition {
+		q.where = appendWhere(q.where, map[string]interface{}{
+			RawKey: val,
+		})
+	}
+}
+
+// AddCondition add typed where condition, conditions are joined with AND
+func (q *deleteBuilder) AddCondition(condition ...Condition) {
+	for _, val := range condition {
+		q.where = appendWhere(q.where, map[string]interface{}{
+			RawKey: val,
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package goutils
+
+import (
+	"testing"
+)
+
+func TestCondition(t *testing.T) {
+	orders := NewQueryBuilder()
+	orders.AddSelection("user_id")
+	orders.AddWhere("status", "", "paid")
+
+	tests := []struct {
+		name      string
+		condition Condition
+		expected  string
+		values    []interface{}
+	}{
+		{"eq", Eq("name", "john"), `"name" = $1`, []interface{}{"john"}},
+		{"eq nil", Eq("name", nil), `"name" IS NULL`, nil},
+		{"neq", Neq("name", "john"), `"name" != $1`, []interface{}{"john"}},
+		{"comparison", And(Gt("age", 1), Gte("age", 2), Lt("age", 3), Lte("age", 4)), `((("age" > $1 AND "age" >= $2) AND "age" < $3) AND "age" <= $4)`, []interface{}{1, 2, 3, 4}},
+		{"in", In("id", []int{1, 2}), `"id" IN ($1, $2)`, []interface{}{1, 2}},
+		{"not in", NotIn("id", []string{"a"}), `"id" NOT IN ($1)`, []interface{}{"a"}},
+		{"in subquery", In("id", Subquery(orders, "orders", "")), `"id" IN (SELECT "user_id" FROM "orders" WHERE "status" = $1)`, []interface{}{"paid"}},
+		{"like", Or(Like("name", "a%"), ILike("name", "b%")), `("name" LIKE $1 OR "name" ILIKE $2)`, []interface{}{"a%", "b%"}},
+		{"between", Between("age", 18, 30), `"age" BETWEEN $1 AND $2`, []interface{}{18, 30}},
+		{"is null", IsNull("deleted_at"), `"deleted_at" IS NULL`, nil},
+		{"is not null", IsNotNull("deleted_at"), `"deleted_at" IS NOT NULL`, nil},
+		{"exists", Exists(Subquery(orders, "orders", "")), `EXISTS (SELECT "user_id" FROM "orders" WHERE "status" = $1)`, []interface{}{"paid"}},
+		{"not", Not(Or(Eq("a", 1), And(Eq("b", 2), IsNull("c")))), `NOT (("a" = $1 OR ("b" = $2 AND "c" IS NULL)))`, []interface{}{1, 2}},
+	}
+
+	for _, test := range tests {
+		query := NewQueryBuilder(WithDialect(PostgreSQL))
+		query.AddCondition(test.condition)
+
+		res, values, err := query.GetQuery("users", "")
+		assertQuery(t, test.name, res, values, err, `SELECT * FROM "users" WHERE `+test.expected, test.values)
+	}
+}
+
+func TestConditionWithMapWhere(t *testing.T) {
+	query := NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddWhere("status", "", "paid")
+	query.AddRawWhere(map[string]interface{}{
+		"gt": map[string]interface{}{"amount": 100},
+		"OR": map[string]interface{}{"a": 1, "b": 2},
+	})
+	query.AddCondition(Or(Eq("c", 3), Not(Eq("d", 4))))
+	query.AddRawWhere(map[string]interface{}{
+		RawKey: And(IsNull("deleted_at"), Between("age", 18, 30)),
+	})
+
+	res, values, err := query.GetQuery("orders", "")
+	assertQuery(t, "mixed", res, values, err,
+		`SELECT * FROM "orders" WHERE ((("status" = $1 AND (("a" = $2 OR "b" = $3) AND "amount" > $4)) AND ("c" = $5 OR NOT ("d" = $6))) AND ("deleted_at" IS NULL AND "age" BETWEEN $7 AND $8))`,
+		[]interface{}{"paid", 1, 2, 100, 3, 4, 18, 30},
+	)
+}
+
+func TestConditionOnUpdateAndDelete(t *testing.T) {
+	update := NewUpdateBuilder(WithDialect(PostgreSQL))
+	update.AddSet("status", "archived")
+	update.AddWhere("status", "", "paid")
+	update.AddCondition(Lt("created_at", "2024-01-01"), Not(In("id", []int{1, 2})))
+
+	res, values, err := update.GetQuery("orders")
+	assertQuery(t, "update", res, values, err,
+		`UPDATE "orders" SET "status" = $1 WHERE (("status" = $2 AND "created_at" < $3) AND NOT ("id" IN ($4, $5)))`,
+		[]interface{}{"archived", "paid", "2024-01-01", 1, 2},
+	)
+
+	remove := NewDeleteBuilder(WithDialect(MySQL))
+	remove.AddCondition(Or(IsNull("user_id"), Eq("status", "void")))
+
+	res, values, err = remove.GetQuery("orders")
+	assertQuery(t, "delete", res, values, err,
+		"DELETE FROM `orders` WHERE (`user_id` IS NULL OR `status` = ?)",
+		[]interface{}{"void"},
+	)
+}
@@ -0,0 +1,117 @@
+package goutils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// =================================================
+
+// commonTable common table expression of with query
+type commonTable struct {
+	name      string
+	columns   []string
+	recursive bool
+	anchor    subquery
+	iterate   *subquery
+}
+
+// AddWith add common table expression, WITH name AS (subquery)
+func (q *queryBuilder) AddWith(name string, subquery subquery) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addWith(commonTable{
+		name:   name,
+		anchor: subquery,
+	})
+	q.addKey("with", name, subquery)
+}
+
+// AddWithRecursive add recursive common table expression,
+// WITH RECURSIVE name (columns) AS (anchor UNION ALL recursive), recursive subquery refer to the name as table
+func (q *queryBuilder) AddWithRecursive(name string, columns []string, anchor subquery, recursive subquery) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addWith(commonTable{
+		name:      name,
+		columns:   columns,
+		recursive: true,
+		anchor:    anchor,
+		iterate:   &recursive,
+	})
+	q.addKey("with_recursive", name, anchor, recursive)
+}
+
+func (q *queryBuilder) addWith(table commonTable) {
+	arrWith := make([]commonTable, 0)
+	if q.with != nil {
+		arrWith = append(arrWith, *q.with...)
+	}
+
+	arrWith = append(arrWith, table)
+	q.with = &arrWith
+}
+
+func parseWith(d dialect, tables []commonTable) (query string, values []interface{}, err error) {
+	var (
+		recursive bool
+		arrWith   = make([]string, 0, len(tables))
+	)
+	for i, table := range tables {
+		var (
+			name    string
+			columns = make([]string, 0, len(table.columns))
+			q       string
+			v       []interface{}
+			path    = []string{"with", strconv.Itoa(i)}
+		)
+		if name, err = d.identifier(table.name); err != nil {
+			return query, values, prependPath(err, path...)
+		}
+
+		for _, val := range table.columns {
+			var column string
+			if column, err = d.identifier(val); err != nil {
+				return query, values, prependPath(err, path...)
+			}
+			columns = append(columns, column)
+		}
+
+		q, v, err = table.anchor.parseQuery(d)
+		if err != nil {
+			return query, values, prependPath(err, path...)
+		}
+		values = append(values, v...)
+
+		if table.recursive {
+			recursive = true
+			if table.iterate == nil {
+				err = prependPath(newQueryError(ErrInvalidValue, table.name, "", nil), path...)
+				return
+			}
+
+			var iterate string
+			iterate, v, err = table.iterate.parseQuery(d)
+			if err != nil {
+				return query, values, prependPath(err, path...)
+			}
+			q = fmt.Sprintf("%s UNION ALL %s", q, iterate)
+			values = append(values, v...)
+		}
+
+		if len(columns) > 0 {
+			name = fmt.Sprintf("%s (%s)", name, strings.Join(columns, ", "))
+		}
+		arrWith = append(arrWith, fmt.Sprintf("%s AS (%s)", name, q))
+	}
+
+	query = "WITH"
+	if recursive {
+		query = "WITH RECURSIVE"
+	}
+	query = fmt.Sprintf("%s %s", query, strings.Join(arrWith, ", "))
+	return
+}
@@ -0,0 +1,62 @@
+package goutils
+
+import (
+	"testing"
+)
+
+func TestWithPlaceholder(t *testing.T) {
+	paid := NewQueryBuilder()
+	paid.AddSelection("user_id")
+	paid.AddWhere("status", "", "paid")
+
+	big := NewQueryBuilder()
+	big.AddSelection("user_id")
+	big.AddWhere("amount", "gt", 100)
+
+	query := NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddWith("paid_orders", Subquery(paid, "orders", ""))
+	query.AddWith("big_orders", Subquery(big, "orders", ""))
+	query.AddWhere("active", "", true)
+	query.AddWhere("id", "in", Subquery(NewQueryBuilder(), "paid_orders", ""))
+	query.AddPagination(NewPagination(1, 10))
+
+	res, values, err := query.GetQuery("users", "")
+	assertQuery(t, "with", res, values, err,
+		`WITH "paid_orders" AS (SELECT "user_id" FROM "orders" WHERE "status" = $1), "big_orders" AS (SELECT "user_id" FROM "orders" WHERE "amount" > $2) `+
+			`SELECT * FROM "users" WHERE ("active" = $3 AND "id" IN (SELECT * FROM "paid_orders")) LIMIT 10 OFFSET 0`,
+		[]interface{}{"paid", 100, true},
+	)
+}
+
+func TestWithRecursive(t *testing.T) {
+	anchor := NewQueryBuilder()
+	anchor.AddSelection("id")
+	anchor.AddSelection("parent_id")
+	anchor.AddWhere("id", "", 1)
+
+	recursive := NewQueryBuilder()
+	recursive.AddSelection("c.id")
+	recursive.AddSelection("c.parent_id")
+	recursive.AddJoin(InnerJoin, "tree", "t", "t.id = c.parent_id")
+	recursive.AddWhere("c.active", "", true)
+
+	query := NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddWithRecursive("tree", []string{"id", "parent_id"}, Subquery(anchor, "categories", ""), Subquery(recursive, "categories", "c"))
+	query.AddWhere("id", "neq", 5)
+
+	res, values, err := query.GetQuery("tree", "")
+	assertQuery(t, "recursive", res, values, err,
+		`WITH RECURSIVE "tree" ("id", "parent_id") AS (SELECT "id", "parent_id" FROM "categories" WHERE "id" = $1 `+
+			`UNION ALL SELECT "c"."id", "c"."parent_id" FROM "categories" "c" INNER JOIN "tree" "t" ON "t"."id" = "c"."parent_id" WHERE "c"."active" = $2) `+
+			`SELECT * FROM "tree" WHERE "id" != $3`,
+		[]interface{}{1, true, 5},
+	)
+
+	res, values, err = query.GetCountQuery("tree", "")
+	assertQuery(t, "recursive count", res, values, err,
+		`WITH RECURSIVE "tree" ("id", "parent_id") AS (SELECT "id", "parent_id" FROM "categories" WHERE "id" = $1 `+
+			`UNION ALL SELECT "c"."id", "c"."parent_id" FROM "categories" "c" INNER JOIN "tree" "t" ON "t"."id" = "c"."parent_id" WHERE "c"."active" = $2) `+
+			`SELECT COUNT(*) FROM "tree" WHERE "id" != $3`,
+		[]interface{}{1, true, 5},
+	)
+}
@@ -0,0 +1,253 @@
+package goutils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// =================================================
+
+type cursorOption struct {
+	limit  int
+	values []interface{}
+	prev   bool
+}
+
+type cursorToken struct {
+	Values []interface{} `json:"v"`
+	Prev   bool          `json:"p,omitempty"`
+}
+
+// NewCursorPagination build new cursor pagination from opaque cursor, empty cursor start from the first row
+func NewCursorPagination(cursor string, limit int) (*cursorOption, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	option := &cursorOption{
+		limit: limit,
+	}
+	if cursor == "" {
+		return option, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor value")
+	}
+
+	var token cursorToken
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&token); err != nil || len(token.Values) == 0 {
+		return nil, errors.New("invalid cursor value")
+	}
+
+	for key, val := range token.Values {
+		if number, ok := val.(json.Number); ok {
+			token.Values[key] = parseCursorNumber(number)
+		}
+	}
+
+	option.values = token.Values
+	option.prev = token.Prev
+	return option, nil
+}
+
+func parseCursorNumber(number json.Number) interface{} {
+	if val, err := number.Int64(); err == nil {
+		return val
+	}
+
+	if val, err := number.Float64(); err == nil {
+		return val
+	}
+
+	return number.String()
+}
+
+// NextCursor build opaque cursor of next page from sort column values of the last row
+func NextCursor(values ...interface{}) string {
+	return encodeCursor(false, values...)
+}
+
+// PrevCursor build opaque cursor of previous page from sort column values of the first row
+func PrevCursor(values ...interface{}) string {
+	return encodeCursor(true, values...)
+}
+
+func encodeCursor(prev bool, values ...interface{}) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	data, err := json.Marshal(cursorToken{
+		Values: values,
+		Prev:   prev,
+	})
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (cursor *cursorOption) GetLimit() int {
+	return cursor.limit
+}
+
+// IsPrev whether cursor fetch previous page, rows are returned in reversed sort order
+func (cursor *cursorOption) IsPrev() bool {
+	return cursor.prev
+}
+
+// IsFirst whether cursor start from the first row
+func (cursor *cursorOption) IsFirst() bool {
+	return len(cursor.values) == 0
+}
+
+// CursorPage trim extra row of cursor pagination query, restore sort order of previous page
+// and build opaque next and prev cursors, values return sort column values of row
+func CursorPage[T any](cursor *cursorOption, rows []T, values func(row T) []interface{}) (items []T, next string, prev string) {
+	hasMore := len(rows) > cursor.limit
+	if hasMore {
+		rows = rows[:cursor.limit]
+	}
+
+	items = make([]T, 0, len(rows))
+	if cursor.prev {
+		for i := len(rows) - 1; i >= 0; i-- {
+			items = append(items, rows[i])
+		}
+	} else {
+		items = append(items, rows...)
+	}
+
+	if len(items) == 0 {
+		return
+	}
+
+	var (
+		hasNext = hasMore
+		hasPrev = !cursor.IsFirst()
+	)
+	if cursor.prev {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	if hasNext {
+		next = NextCursor(values(items[len(items)-1])...)
+	}
+
+	if hasPrev {
+		prev = PrevCursor(values(items[0])...)
+	}
+	return
+}
+
+// =================================================
+
+// AddCursorPagination add keyset pagination query built from the sort columns,
+// previous page is fetched in reversed sort order and limit + 1 rows are fetched, use CursorPage to trim the rows
+func (q *queryBuilder) AddCursorPagination(cursor *cursorOption) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.cursor = cursor
+	q.addKey("cursor", encodeCursor(cursor.prev, cursor.values...))
+	q.addKey("limit", cursor.limit)
+}
+
+// cursorSort sort of cursor pagination, direction is reversed for previous page
+func cursorSort(cursor cursorOption, sorts []sortItem) []sortItem {
+	if !cursor.prev {
+		return sorts
+	}
+
+	reversed := make([]sortItem, 0, len(sorts))
+	for _, val := range sorts {
+		dir := DirDesc
+		if val.direction == DirDesc {
+			dir = DirAsc
+		}
+		reversed = append(reversed, sortItem{
+			column:    val.column,
+			direction: dir,
+		})
+	}
+	return reversed
+}
+
+// parseCursor parse keyset predicate, (a, b) < (?, ?) when every direction is equal
+// or (a > ? OR (a = ? AND b < ?)) when direction is mixed
+func parseCursor(d dialect, cursor cursorOption, sorts []sortItem) (query string, values []interface{}, err error) {
+	if len(sorts) == 0 {
+		err = prependPath(newQueryError(ErrInvalidValue, "sort", "", nil), "cursor")
+		return
+	}
+
+	if cursor.IsFirst() {
+		return
+	}
+
+	if len(cursor.values) != len(sorts) {
+		err = prependPath(newQueryError(ErrInvalidValue, "", "", cursor.values), "cursor")
+		return
+	}
+
+	var (
+		columns   = make([]string, 0, len(sorts))
+		operators = make([]string, 0, len(sorts))
+		isMixed   bool
+	)
+	for key, val := range sorts {
+		operator := ">"
+		if val.direction == DirDesc {
+			operator = "<"
+		}
+		if key > 0 && operator != operators[0] {
+			isMixed = true
+		}
+
+		var column string
+		if column, err = d.column(val.column, false); err != nil {
+			return query, values, prependPath(err, "cursor")
+		}
+		columns = append(columns, column)
+		operators = append(operators, operator)
+	}
+
+	if !isMixed {
+		if len(columns) == 1 {
+			query = fmt.Sprintf("%s %s ?", columns[0], operators[0])
+		} else {
+			placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+			query = fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), operators[0], placeholders)
+		}
+		values = append(values, cursor.values...)
+		return
+	}
+
+	arrQuery := make([]string, 0, len(columns))
+	for key := range columns {
+		conditions := make([]string, 0, key+1)
+		for i := 0; i < key; i++ {
+			conditions = append(conditions, fmt.Sprintf("%s = ?", columns[i]))
+			values = append(values, cursor.values[i])
+		}
+		conditions = append(conditions, fmt.Sprintf("%s %s ?", columns[key], operators[key]))
+		values = append(values, cursor.values[key])
+
+		if len(conditions) > 1 {
+			arrQuery = append(arrQuery, fmt.Sprintf("(%s)", strings.Join(conditions, " AND ")))
+		} else {
+			arrQuery = append(arrQuery, conditions[0])
+		}
+	}
+	query = fmt.Sprintf("(%s)", strings.Join(arrQuery, " OR "))
+	return
+}
@@ -0,0 +1,47 @@
+package goutils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCursorPaginationQuery(t *testing.T) {
+	cursor, err := NewCursorPagination(NextCursor(int64(10)), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddSort(DirAsc, "id")
+	query.AddCursorPagination(cursor)
+
+	res, values, err := query.GetQuery("users", "")
+	assertQuery(t, "next", res, values, err,
+		`SELECT * FROM "users" WHERE "id" > $1 ORDER BY "id" ASC LIMIT 3`,
+		[]interface{}{int64(10)},
+	)
+}
+
+func TestCursorPage(t *testing.T) {
+	id := func(row int64) []interface{} {
+		return []interface{}{row}
+	}
+
+	first, _ := NewCursorPagination("", 2)
+	items, next, prev := CursorPage(first, []int64{1, 2, 3}, id)
+	if !reflect.DeepEqual(items, []int64{1, 2}) || next != NextCursor(int64(2)) || prev != "" {
+		t.Errorf("first page: got %v %q %q", items, next, prev)
+	}
+
+	last, _ := NewCursorPagination(next, 2)
+	items, next, prev = CursorPage(last, []int64{3}, id)
+	if !reflect.DeepEqual(items, []int64{3}) || next != "" || prev != PrevCursor(int64(3)) {
+		t.Errorf("last page: got %v %q %q", items, next, prev)
+	}
+
+	back, _ := NewCursorPagination(prev, 2)
+	items, next, prev = CursorPage(back, []int64{2, 1}, id)
+	if !reflect.DeepEqual(items, []int64{1, 2}) || next != NextCursor(int64(2)) || prev != "" {
+		t.Errorf("previous page: got %v %q %q", items, next, prev)
+	}
+}
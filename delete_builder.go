@@ -0,0 +1,130 @@
+package goutils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Muruyung/go-utilities/converter"
+)
+
+// DefaultSoftDeleteColumn default column of soft delete
+const DefaultSoftDeleteColumn = "deleted_at"
+
+// =================================================
+
+// DeleteBuilderInteractor delete query builder interactor
+type DeleteBuilderInteractor interface {
+	GetQuery(tablename string) (query string, values []interface{}, err error)
+	AddWhere(attribute string, operation string, value interface{})
+	AddRawWhere(listWhere map[string]interface{})
+	AddCondition(condition ...Condition)
+	AllowEmptyWhere()
+	SetSoftDelete(column string, deletedAt time.Time)
+}
+
+type deleteBuilder struct {
+	where           *[]map[string]interface{}
+	allowEmptyWhere bool
+	softDelete      *softDelete
+	option          builderOption
+}
+
+type softDelete struct {
+	column    string
+	deletedAt time.Time
+}
+
+// NewDeleteBuilder create new delete query builder
+func NewDeleteBuilder(opts ...QueryBuilderOption) DeleteBuilderInteractor {
+	return &deleteBuilder{
+		where:      nil,
+		softDelete: nil,
+		option:     newBuilderOption(opts...),
+	}
+}
+
+// GetQuery parse delete query, or update query when soft delete is set
+func (q *deleteBuilder) GetQuery(tablename string) (query string, values []interface{}, err error) {
+	if q.softDelete != nil {
+		return q.getSoftDeleteQuery(tablename)
+	}
+
+	var (
+		d     = q.option.dialect
+		table string
+		where string
+	)
+	if table, err = d.identifier(tablename); err != nil {
+		return
+	}
+
+	if q.where != nil {
+		if where, values, err = parseWhereList(d, "where", *q.where); err != nil {
+			return "", nil, err
+		}
+	}
+
+	// where which is rendered empty, e.g. AddRawWhere of empty map, is treated as no where
+	if where == "" && !q.allowEmptyWhere {
+		err = fmt.Errorf("%w: delete without where is not allowed, use AllowEmptyWhere to delete all rows", ErrEmptyWhere)
+		return "", nil, err
+	}
+
+	query = fmt.Sprintf(`DELETE FROM %s`, table)
+	if where != "" {
+		query = fmt.Sprintf(`%s WHERE %s`, query, where)
+	}
+
+	query = d.rebind(query)
+	return
+}
+
+func (q *deleteBuilder) getSoftDeleteQuery(tablename string) (query string, values []interface{}, err error) {
+	deletedAt := q.softDelete.deletedAt
+	if deletedAt.IsZero() {
+		deletedAt = time.Now()
+	}
+
+	update := &updateBuilder{
+		where:           q.where,
+		allowEmptyWhere: q.allowEmptyWhere,
+		option:          q.option,
+	}
+	update.AddSet(q.softDelete.column, converter.ConvertDateToString(deletedAt))
+
+	return update.GetQuery(tablename)
+}
+
+// AllowEmptyWhere allow delete query without where, which delete all rows of table
+func (q *deleteBuilder) AllowEmptyWhere() {
+	q.allowEmptyWhere = true
+}
+
+// SetSoftDelete rewrite delete into update of column, default column is deleted_at and default time is now
+func (q *deleteBuilder) SetSoftDelete(column string, deletedAt time.Time) {
+	if column == "" {
+		column = DefaultSoftDeleteColumn
+	}
+
+	q.softDelete = &softDelete{
+		column:    column,
+		deletedAt: deletedAt,
+	}
+}
+
+// =================================================
+
+// AddWhere add where query, using the same condition grammar as query builder
+func (q *deleteBuilder) AddWhere(attribute string, operation string, value interface{}) {
+	q.where = appendWhere(q.where, buildWhere(attribute, operation, value))
+}
+
+// AddRawWhere add raw where query
+func (q *deleteBuilder) AddRawWhere(listWhere map[string]interface{}) {
+	where := make(map[string]interface{})
+	for key, val := range listWhere {
+		where[key] = val
+	}
+
+	q.where = appendWhere(q.where, where)
+}
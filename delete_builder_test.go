@@ -0,0 +1,82 @@
+package goutils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Muruyung/go-utilities/converter"
+)
+
+func TestDeleteQuery(t *testing.T) {
+	query := NewDeleteBuilder(WithDialect(PostgreSQL))
+	query.AddWhere("id", "", 10)
+	query.AddWhere("status", "neq", "active")
+
+	res, values, err := query.GetQuery("users")
+	assertQuery(t, "delete", res, values, err,
+		`DELETE FROM "users" WHERE ("id" = $1 AND "status" != $2)`,
+		[]interface{}{10, "active"},
+	)
+}
+
+func TestDeleteQueryWithoutWhere(t *testing.T) {
+	tests := map[string][]map[string]interface{}{
+		"no where":        nil,
+		"empty raw where": {{}},
+		"empty AND list":  {{"AND": []map[string]interface{}{}}},
+	}
+
+	for name, listWhere := range tests {
+		for _, soft := range []bool{false, true} {
+			query := NewDeleteBuilder(WithDialect(PostgreSQL))
+			for _, where := range listWhere {
+				query.AddRawWhere(where)
+			}
+
+			if soft {
+				query.SetSoftDelete("", time.Time{})
+			}
+
+			res, values, err := query.GetQuery("users")
+			if !errors.Is(err, ErrEmptyWhere) {
+				t.Errorf("%s: expected ErrEmptyWhere, got %v", name, err)
+			}
+
+			if res != "" || values != nil {
+				t.Errorf("%s: expected empty query, got %q %v", name, res, values)
+			}
+		}
+	}
+
+	query := NewDeleteBuilder(WithDialect(PostgreSQL))
+	query.AddRawWhere(map[string]interface{}{})
+	query.AllowEmptyWhere()
+
+	res, values, err := query.GetQuery("users")
+	assertQuery(t, "allow empty where", res, values, err, `DELETE FROM "users"`, nil)
+}
+
+func TestSoftDeleteQuery(t *testing.T) {
+	deletedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	query := NewDeleteBuilder(WithDialect(PostgreSQL))
+	query.AddWhere("id", "", 10)
+	query.SetSoftDelete("", deletedAt)
+
+	res, values, err := query.GetQuery("users")
+	assertQuery(t, "default column", res, values, err,
+		`UPDATE "users" SET "deleted_at" = $1 WHERE "id" = $2`,
+		[]interface{}{converter.ConvertDateToString(deletedAt), 10},
+	)
+
+	query = NewDeleteBuilder(WithDialect(MySQL))
+	query.AddWhere("id", "", 10)
+	query.SetSoftDelete("removed_at", deletedAt)
+
+	res, values, err = query.GetQuery("users")
+	assertQuery(t, "custom column", res, values, err,
+		"UPDATE `users` SET `removed_at` = ? WHERE `id` = ?",
+		[]interface{}{converter.ConvertDateToString(deletedAt), 10},
+	)
+}
@@ -0,0 +1,211 @@
+package goutils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// =================================================
+
+// dialect sql dialect
+type dialect struct {
+	name string
+}
+
+var (
+	// PostgreSQL postgresql dialect, placeholder $1..$n
+	PostgreSQL = dialect{
+		name: "postgres",
+	}
+
+	// MySQL mysql dialect, placeholder ?
+	MySQL = dialect{
+		name: "mysql",
+	}
+
+	// SQLite sqlite dialect, placeholder ?
+	SQLite = dialect{
+		name: "sqlite",
+	}
+
+	// Dialect map dialect
+	Dialect = map[string]dialect{
+		"postgres": PostgreSQL,
+		"mysql":    MySQL,
+		"sqlite":   SQLite,
+	}
+
+	// defaultDialect keep legacy output: postgres operators with ? placeholder and unquoted identifiers
+	defaultDialect = dialect{
+		name: "",
+	}
+
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+)
+
+// =================================================
+
+// QueryBuilderOption query builder option
+type QueryBuilderOption func(option *builderOption)
+
+type builderOption struct {
+	dialect   dialect
+	namespace string
+}
+
+// WithDialect set sql dialect of query builder
+func WithDialect(dialect dialect) QueryBuilderOption {
+	return func(option *builderOption) {
+		option.dialect = dialect
+	}
+}
+
+func newBuilderOption(opts ...QueryBuilderOption) builderOption {
+	option := builderOption{
+		dialect: defaultDialect,
+	}
+	for _, opt := range opts {
+		opt(&option)
+	}
+
+	return option
+}
+
+// =================================================
+
+// rebind replace ? placeholder into dialect placeholder and escaped ?? into literal ?, quoted string is skipped
+func (d dialect) rebind(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var (
+		builder strings.Builder
+		count   int
+		quote   rune
+		chars   = []rune(query)
+	)
+	for i := 0; i < len(chars); i++ {
+		char := chars[i]
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '\'' || char == '"' || char == '`':
+			quote = char
+		case char == '?' && i+1 < len(chars) && chars[i+1] == '?':
+			i++
+		case char == '?' && d == PostgreSQL:
+			count++
+			builder.WriteString("$")
+			builder.WriteString(strconv.Itoa(count))
+			continue
+		}
+		builder.WriteRune(char)
+	}
+
+	return builder.String()
+}
+
+// quoteIdentifier quote plain identifier (table, table.column), other expression is returned as is
+func (d dialect) quoteIdentifier(identifier string) string {
+	if d == defaultDialect || !identifierPattern.MatchString(identifier) {
+		return identifier
+	}
+
+	quote := `"`
+	if d == MySQL {
+		quote = "`"
+	}
+
+	parts := strings.Split(identifier, ".")
+	for key, val := range parts {
+		parts[key] = quote + val + quote
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// concat concatenation of sql expression
+func (d dialect) concat(expr ...string) string {
+	if d == MySQL {
+		return "CONCAT(" + strings.Join(expr, ", ") + ")"
+	}
+
+	return strings.Join(expr, " || ")
+}
+
+// expandIn whether IN operator is expanded into IN (?, ?, ...) instead of = ANY(?),
+// only default dialect keeps legacy = ANY(?) which needs driver with array support, e.g. pq.Array of lib/pq
+func (d dialect) expandIn() bool {
+	return d != defaultDialect
+}
+
+// like render case sensitive or insensitive like, ILIKE is emulated with LOWER() when not supported
+func (d dialect) like(key string, not bool, insensitive bool, pattern string) string {
+	operator := "LIKE"
+	if not {
+		operator = "NOT LIKE"
+	}
+
+	if !insensitive {
+		return key + " " + operator + " " + pattern
+	}
+
+	if d == MySQL || d == SQLite {
+		return "LOWER(" + key + ") " + operator + " LOWER(" + pattern + ")"
+	}
+
+	return key + " " + strings.Replace(operator, "LIKE", "ILIKE", 1) + " " + pattern
+}
+
+// regexp render regular expression match
+func (d dialect) regexp(key string, not bool, insensitive bool) string {
+	switch d {
+	case MySQL:
+		if insensitive {
+			if not {
+				return "NOT REGEXP_LIKE(" + key + ", ?, 'i')"
+			}
+			return "REGEXP_LIKE(" + key + ", ?, 'i')"
+		}
+		if not {
+			return key + " NOT REGEXP ?"
+		}
+		return key + " REGEXP ?"
+	case SQLite:
+		pattern := "?"
+		if insensitive {
+			pattern = d.concat("'(?i)'", "?")
+		}
+		if not {
+			return key + " NOT REGEXP " + pattern
+		}
+		return key + " REGEXP " + pattern
+	default:
+		operator := "~"
+		if insensitive {
+			operator = "~*"
+		}
+		if not {
+			operator = "!" + operator
+		}
+		return key + " " + operator + " ?"
+	}
+}
+
+// supportReturning whether RETURNING clause is supported
+func (d dialect) supportReturning() bool {
+	return d != MySQL
+}
+
+// quoteString quote string literal
+func (d dialect) quoteString(value string) string {
+	if d == MySQL {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
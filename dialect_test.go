@@ -0,0 +1,39 @@
+package goutils
+
+import (
+	"testing"
+)
+
+func TestDialectOperation(t *testing.T) {
+	tests := []struct {
+		dialect   dialect
+		operation string
+		value     interface{}
+		expected  string
+		values    []interface{}
+	}{
+		{defaultDialect, "in", []int{1, 2}, `SELECT * FROM users WHERE id = ANY(?)`, []interface{}{[]int{1, 2}}},
+		{PostgreSQL, "in", []int{1, 2}, `SELECT * FROM "users" WHERE "id" IN ($1, $2)`, []interface{}{1, 2}},
+		{PostgreSQL, "not_in", []interface{}{"a", "b"}, `SELECT * FROM "users" WHERE "id" NOT IN ($1, $2)`, []interface{}{"a", "b"}},
+		{MySQL, "in", []int{1, 2}, "SELECT * FROM `users` WHERE `id` IN (?, ?)", []interface{}{1, 2}},
+		{SQLite, "not_in", []int{1, 2}, `SELECT * FROM "users" WHERE "id" NOT IN (?, ?)`, []interface{}{1, 2}},
+		{PostgreSQL, "i_like", "a%", `SELECT * FROM "users" WHERE "id" ILIKE $1`, []interface{}{"a%"}},
+		{MySQL, "i_like", "a%", "SELECT * FROM `users` WHERE LOWER(`id`) LIKE LOWER(?)", []interface{}{"a%"}},
+		{PostgreSQL, "substring", "a", `SELECT * FROM "users" WHERE "id" LIKE '%' || $1 || '%'`, []interface{}{"a"}},
+		{MySQL, "substring", "a", "SELECT * FROM `users` WHERE `id` LIKE CONCAT('%', ?, '%')", []interface{}{"a"}},
+		{PostgreSQL, "i_regexp", "^a", `SELECT * FROM "users" WHERE "id" ~* $1`, []interface{}{"^a"}},
+		{PostgreSQL, "not_i_regexp", "^a", `SELECT * FROM "users" WHERE "id" !~* $1`, []interface{}{"^a"}},
+		{MySQL, "regexp", "^a", "SELECT * FROM `users` WHERE `id` REGEXP ?", []interface{}{"^a"}},
+		{PostgreSQL, "between", []int{1, 2}, `SELECT * FROM "users" WHERE "id" BETWEEN $1 AND $2`, []interface{}{1, 2}},
+		{PostgreSQL, "is", nil, `SELECT * FROM "users" WHERE "id" IS NULL`, nil},
+		{PostgreSQL, "neq", nil, `SELECT * FROM "users" WHERE "id" IS NOT NULL`, nil},
+	}
+
+	for _, test := range tests {
+		query := NewQueryBuilder(WithDialect(test.dialect))
+		query.AddWhere("id", test.operation, test.value)
+
+		res, values, err := query.GetQuery("users", "")
+		assertQuery(t, test.dialect.name+" "+test.operation, res, values, err, test.expected, test.values)
+	}
+}
@@ -0,0 +1,81 @@
+package goutils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// =================================================
+
+var (
+	// ErrInvalidOperator operator is unknown or not supported for the value
+	ErrInvalidOperator = errors.New("invalid operator")
+
+	// ErrInvalidValue value has invalid type or shape for the operator
+	ErrInvalidValue = errors.New("invalid value")
+
+	// ErrEmptyInList list value of in operator is empty
+	ErrEmptyInList = errors.New("empty list value")
+
+	// ErrInvalidIdentifier identifier is not a valid column, table or aliases
+	ErrInvalidIdentifier = errors.New("invalid identifier")
+
+	// ErrEmptyWhere update or delete query without where
+	ErrEmptyWhere = errors.New("empty where")
+)
+
+// QueryError error of query building, use errors.Is with the sentinel error or errors.As to inspect it
+type QueryError struct {
+	Err       error
+	Attribute string
+	Operator  string
+	Value     interface{}
+	Path      []string
+}
+
+// Error error message of query building
+func (err *QueryError) Error() string {
+	message := err.Err.Error()
+	if err.Attribute != "" {
+		message = fmt.Sprintf("%s for %q", message, err.Attribute)
+	}
+
+	if err.Operator != "" {
+		message = fmt.Sprintf("%s with operator %s", message, err.Operator)
+	}
+
+	if err.Value != nil {
+		message = fmt.Sprintf("%s: %v", message, err.Value)
+	}
+
+	if len(err.Path) > 0 {
+		message = fmt.Sprintf("%s at %s", message, strings.Join(err.Path, "."))
+	}
+
+	return message
+}
+
+// Unwrap sentinel error of query error
+func (err *QueryError) Unwrap() error {
+	return err.Err
+}
+
+func newQueryError(err error, attribute string, operator string, value interface{}) *QueryError {
+	return &QueryError{
+		Err:       err,
+		Attribute: attribute,
+		Operator:  operator,
+		Value:     value,
+	}
+}
+
+// prependPath prepend key into path of query error, so the path is built from the root of condition tree
+func prependPath(err error, key ...string) error {
+	var queryErr *QueryError
+	if errors.As(err, &queryErr) {
+		queryErr.Path = append(append([]string{}, key...), queryErr.Path...)
+	}
+
+	return err
+}
@@ -0,0 +1,113 @@
+package goutils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func assertQueryError(t *testing.T, name string, err error, sentinel error, attribute string, path string) {
+	t.Helper()
+	if !errors.Is(err, sentinel) {
+		t.Errorf("%s: expected %v, got %v", name, sentinel, err)
+		return
+	}
+
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) {
+		t.Errorf("%s: expected QueryError, got %T", name, err)
+		return
+	}
+
+	if queryErr.Attribute != attribute || strings.Join(queryErr.Path, ".") != path {
+		t.Errorf("%s: expected attribute %q at %q, got %q at %q", name, attribute, path, queryErr.Attribute, strings.Join(queryErr.Path, "."))
+	}
+}
+
+func TestQueryBuilderError(t *testing.T) {
+	cursor, _ := NewCursorPagination("", 10)
+	tests := []struct {
+		name      string
+		build     func(query QueryBuilderInteractor)
+		sentinel  error
+		attribute string
+		path      string
+	}{
+		{"selection", func(query QueryBuilderInteractor) { query.AddSelection("id"); query.AddSelection("id; drop") }, ErrInvalidIdentifier, "id; drop", "selection.1"},
+		{"window function", func(query QueryBuilderInteractor) { query.AddWindowFunction(windowFunction{}, nil, "n") }, ErrInvalidOperator, "", "selection.0"},
+		{"sort direction", func(query QueryBuilderInteractor) { query.AddSort(direction{}, "id") }, ErrInvalidValue, "id", "sort.0"},
+		{"sort column", func(query QueryBuilderInteractor) { query.AddSort(DirAsc, "id", "id desc") }, ErrInvalidIdentifier, "id desc", "sort.1"},
+		{"group", func(query QueryBuilderInteractor) { query.AddGroup("lower(name)") }, ErrInvalidIdentifier, "lower(name)", "group.0"},
+		{"join type", func(query QueryBuilderInteractor) { query.AddJoin(joinType{}, "users", "u", "u.id = o.user_id") }, ErrInvalidOperator, "users", "join.0"},
+		{"join condition", func(query QueryBuilderInteractor) { query.AddJoin(LeftJoin, "users", "u", "u.id") }, ErrInvalidIdentifier, "u.id", "join.0.on"},
+		{"join column", func(query QueryBuilderInteractor) { query.AddJoin(LeftJoin, "users", "u", "u.id = lower(o.id)") }, ErrInvalidIdentifier, "lower(o.id)", "join.0.on"},
+		{"set operation", func(query QueryBuilderInteractor) {
+			query.AddSetOperation(setOperation{}, Subquery(NewQueryBuilder(), "admins", ""))
+		}, ErrInvalidOperator, "admins", "set_operation.0"},
+		{"with", func(query QueryBuilderInteractor) {
+			query.AddWith("paid orders", Subquery(NewQueryBuilder(), "orders", ""))
+		}, ErrInvalidIdentifier, "paid orders", "with.0"},
+		{"subquery", func(query QueryBuilderInteractor) { query.AddFrom(Subquery(nil, "orders", "o")) }, ErrInvalidValue, "orders", ""},
+		{"cursor", func(query QueryBuilderInteractor) { query.AddCursorPagination(cursor) }, ErrInvalidValue, "sort", "cursor"},
+		{"where", func(query QueryBuilderInteractor) { query.AddWhere("id", "gt", 1); query.AddWhere("id", "bogus", 1) }, ErrInvalidOperator, "id", "where.1.bogus.id"},
+	}
+
+	for _, test := range tests {
+		query := NewQueryBuilder(WithDialect(PostgreSQL))
+		test.build(query)
+
+		res, _, err := query.GetQuery("orders", "o")
+		assertQueryError(t, test.name, err, test.sentinel, test.attribute, test.path)
+		if res != "" {
+			t.Errorf("%s: expected empty query, got %q", test.name, res)
+		}
+	}
+}
+
+func TestInsertAndUpdateBuilderError(t *testing.T) {
+	insert := NewInsertBuilder(WithDialect(PostgreSQL))
+	_, _, err := insert.GetQuery("users")
+	assertQueryError(t, "no row", err, ErrInvalidValue, "users", "rows")
+
+	insert.AddRow(map[string]interface{}{"id": 1}, map[string]interface{}{"name": "john"})
+	_, _, err = insert.GetQuery("users")
+	assertQueryError(t, "row columns", err, ErrInvalidValue, "", "rows.1")
+
+	insert = NewInsertBuilder(WithDialect(MySQL))
+	insert.AddRow(map[string]interface{}{"id": 1})
+	insert.AddReturning("id")
+	_, _, err = insert.GetQuery("users")
+	assertQueryError(t, "returning", err, ErrInvalidOperator, "RETURNING", "")
+
+	insert = NewInsertBuilder(WithDialect(PostgreSQL))
+	insert.AddRow(map[string]interface{}{"id": 1, "name": "john"})
+	insert.AddOnConflictUpdate(nil, "name")
+	_, _, err = insert.GetQuery("users")
+	assertQueryError(t, "conflict columns", err, ErrInvalidValue, "", "on_conflict.columns")
+
+	insert = NewInsertBuilder(WithDialect(PostgreSQL))
+	insert.AddRow(map[string]interface{}{"id": 1})
+	insert.AddOnConflictUpdate([]string{"id"})
+	_, _, err = insert.GetQuery("users")
+	assertQueryError(t, "conflict set", err, ErrInvalidValue, "", "on_conflict.set")
+
+	update := NewUpdateBuilder(WithDialect(PostgreSQL))
+	_, _, err = update.GetQuery("users")
+	assertQueryError(t, "no set", err, ErrInvalidValue, "users", "set")
+
+	update.AddSet("name", "john")
+	update.AddSet("full name", "john doe")
+	update.AddWhere("id", "", 1)
+	_, _, err = update.GetQuery("users")
+	assertQueryError(t, "set column", err, ErrInvalidIdentifier, "full name", "set.1")
+}
+
+func TestQueryErrorMessage(t *testing.T) {
+	query := NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddSort(DirAsc, "id desc")
+
+	_, _, err := query.GetQuery("users", "")
+	if expected := `invalid identifier for "id desc" at sort.0`; err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
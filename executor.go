@@ -0,0 +1,247 @@
+package goutils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// =================================================
+
+// Querier database which can run query, e.g. *sql.DB, *sql.Tx or *sql.Conn
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// ExecutorInteractor executor interactor, run query builder and scan the result
+type ExecutorInteractor interface {
+	Select(ctx context.Context, dest interface{}, query QueryBuilderInteractor, tablename string, aliases string) error
+	Get(ctx context.Context, dest interface{}, query QueryBuilderInteractor, tablename string, aliases string) error
+	Count(ctx context.Context, query QueryBuilderInteractor, tablename string, aliases string) (int, error)
+	Exec(ctx context.Context, query string, values []interface{}) (sql.Result, error)
+}
+
+type executor struct {
+	db Querier
+}
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// NewExecutor create new executor of *sql.DB or *sql.Tx
+func NewExecutor(db Querier) ExecutorInteractor {
+	return &executor{
+		db: db,
+	}
+}
+
+// Select run query and scan every row into dest, dest is pointer of slice of struct with db tag,
+// slice of map[string]interface{} or slice of single value
+func (e *executor) Select(ctx context.Context, dest interface{}, query QueryBuilderInteractor, tablename string, aliases string) error {
+	sliceValue := reflect.ValueOf(dest)
+	if sliceValue.Kind() != reflect.Ptr || sliceValue.IsNil() || sliceValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("invalid destination %T, expected pointer of slice", dest)
+	}
+
+	rows, err := e.query(ctx, query, tablename, aliases)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	var (
+		sliceType = sliceValue.Elem().Type()
+		itemType  = sliceType.Elem()
+		isPointer = itemType.Kind() == reflect.Ptr
+		result    = reflect.MakeSlice(sliceType, 0, 0)
+	)
+	if isPointer {
+		itemType = itemType.Elem()
+	}
+
+	for rows.Next() {
+		item := reflect.New(itemType)
+		if err = scanRow(rows, columns, item); err != nil {
+			return err
+		}
+
+		if isPointer {
+			result = reflect.Append(result, item)
+		} else {
+			result = reflect.Append(result, item.Elem())
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	sliceValue.Elem().Set(result)
+	return nil
+}
+
+// Get run query and scan first row into dest, dest is pointer of struct with db tag,
+// map[string]interface{} or single value. sql.ErrNoRows is returned when there is no row
+func (e *executor) Get(ctx context.Context, dest interface{}, query QueryBuilderInteractor, tablename string, aliases string) error {
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("invalid destination %T, expected pointer", dest)
+	}
+
+	rows, err := e.query(ctx, query, tablename, aliases)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	if err = scanRow(rows, columns, value); err != nil {
+		return err
+	}
+
+	return rows.Close()
+}
+
+// Count run count query of query builder, see GetCountQuery
+func (e *executor) Count(ctx context.Context, query QueryBuilderInteractor, tablename string, aliases string) (int, error) {
+	sqlQuery, values, err := query.GetCountQuery(tablename, aliases)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err = e.db.QueryRowContext(ctx, sqlQuery, values...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Exec run query without result rows, e.g. query of insert, update or delete builder
+func (e *executor) Exec(ctx context.Context, query string, values []interface{}) (sql.Result, error) {
+	return e.db.ExecContext(ctx, query, values...)
+}
+
+func (e *executor) query(ctx context.Context, query QueryBuilderInteractor, tablename string, aliases string) (*sql.Rows, error) {
+	sqlQuery, values, err := query.GetQuery(tablename, aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.db.QueryContext(ctx, sqlQuery, values...)
+}
+
+// =================================================
+
+// scanRow scan current row into item, item is pointer of struct, map or single value
+func scanRow(rows *sql.Rows, columns []string, item reflect.Value) error {
+	itemType := item.Type().Elem()
+	switch {
+	case itemType.Kind() == reflect.Map:
+		if itemType.Key().Kind() != reflect.String || itemType.Elem().Kind() != reflect.Interface {
+			return fmt.Errorf("invalid destination %v, expected map[string]interface{}", itemType)
+		}
+
+		data, err := scanMap(rows, columns)
+		if err != nil {
+			return err
+		}
+
+		item.Elem().Set(reflect.ValueOf(data))
+		return nil
+	case itemType.Kind() == reflect.Struct && !reflect.PtrTo(itemType).Implements(scannerType) && itemType.PkgPath() != "time":
+		fields := structFields(itemType)
+		targets := make([]interface{}, 0, len(columns))
+		for _, column := range columns {
+			index, ok := fields[strings.ToLower(column)]
+			if !ok {
+				targets = append(targets, new(interface{}))
+				continue
+			}
+
+			targets = append(targets, item.Elem().FieldByIndex(index).Addr().Interface())
+		}
+
+		return rows.Scan(targets...)
+	default:
+		if len(columns) != 1 {
+			return fmt.Errorf("invalid destination %v for %d columns, expected struct or map", itemType, len(columns))
+		}
+
+		return rows.Scan(item.Interface())
+	}
+}
+
+func scanMap(rows *sql.Rows, columns []string) (map[string]interface{}, error) {
+	values := make([]interface{}, len(columns))
+	targets := make([]interface{}, len(columns))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+
+	if err := rows.Scan(targets...); err != nil {
+		return nil, err
+	}
+
+	data := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		if val, ok := values[i].([]byte); ok {
+			data[column] = string(val)
+		} else {
+			data[column] = values[i]
+		}
+	}
+	return data, nil
+}
+
+// structFields map column of db tag into field index, field of embedded struct is included
+// and field without db tag use lower case field name
+func structFields(structType reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := field.Tag.Get("db")
+		if tag == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && tag == "" {
+			for column, index := range structFields(field.Type) {
+				if _, ok := fields[column]; !ok {
+					fields[column] = append([]int{i}, index...)
+				}
+			}
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		column := strings.Split(tag, ",")[0]
+		if column == "" {
+			column = field.Name
+		}
+		fields[strings.ToLower(column)] = []int{i}
+	}
+
+	return fields
+}
@@ -0,0 +1,249 @@
+package goutils
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stubDriver database/sql driver which return rows of the longest matched query prefix
+type stubDriver struct {
+	results map[string]*stubRows
+	queries []string
+	args    [][]driver.Value
+}
+
+type stubConn struct {
+	driver *stubDriver
+}
+
+type stubStmt struct {
+	conn  *stubConn
+	query string
+}
+
+type stubRows struct {
+	columns []string
+	data    [][]driver.Value
+	index   int
+}
+
+var stub = &stubDriver{}
+
+func init() {
+	sql.Register("goutils_stub", stub)
+}
+
+func (d *stubDriver) Open(string) (driver.Conn, error) {
+	return &stubConn{driver: d}, nil
+}
+
+func (c *stubConn) Prepare(query string) (driver.Stmt, error) {
+	return &stubStmt{conn: c, query: query}, nil
+}
+
+func (c *stubConn) Close() error {
+	return nil
+}
+
+func (c *stubConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transaction is not supported")
+}
+
+func (s *stubStmt) Close() error {
+	return nil
+}
+
+func (s *stubStmt) NumInput() int {
+	return -1
+}
+
+func (s *stubStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.conn.driver.queries = append(s.conn.driver.queries, s.query)
+	s.conn.driver.args = append(s.conn.driver.args, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *stubStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.conn.driver.queries = append(s.conn.driver.queries, s.query)
+	s.conn.driver.args = append(s.conn.driver.args, args)
+	var match string
+	for prefix := range s.conn.driver.results {
+		if strings.HasPrefix(s.query, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+
+	rows, ok := s.conn.driver.results[match]
+	if !ok {
+		return nil, errors.New("unexpected query " + s.query)
+	}
+	return &stubRows{columns: rows.columns, data: rows.data}, nil
+}
+
+func (r *stubRows) Columns() []string {
+	return r.columns
+}
+
+func (r *stubRows) Close() error {
+	return nil
+}
+
+func (r *stubRows) Next(dest []driver.Value) error {
+	if r.index >= len(r.data) {
+		return io.EOF
+	}
+	copy(dest, r.data[r.index])
+	r.index++
+	return nil
+}
+
+func newStubExecutor(t *testing.T, results map[string]*stubRows) ExecutorInteractor {
+	t.Helper()
+	stub.results, stub.queries, stub.args = results, nil, nil
+
+	db, err := sql.Open("goutils_stub", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return NewExecutor(db)
+}
+
+type stubBase struct {
+	CreatedBy string `db:"created_by"`
+}
+
+type stubUser struct {
+	stubBase
+	ID       int64  `db:"id"`
+	Name     string `db:"name"`
+	Password string `db:"-"`
+}
+
+var stubUserRows = &stubRows{
+	columns: []string{"id", "name", "created_by", "unknown"},
+	data: [][]driver.Value{
+		{int64(1), []byte("alice"), "admin", "x"},
+		{int64(2), []byte("bob"), "system", "y"},
+	},
+}
+
+func TestExecutorSelect(t *testing.T) {
+	executor := newStubExecutor(t, map[string]*stubRows{"SELECT": stubUserRows})
+	query := NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddWhere("id", "gt", 0)
+
+	var users []stubUser
+	if err := executor.Select(context.Background(), &users, query, "users", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []stubUser{
+		{stubBase: stubBase{CreatedBy: "admin"}, ID: 1, Name: "alice"},
+		{stubBase: stubBase{CreatedBy: "system"}, ID: 2, Name: "bob"},
+	}
+	if !reflect.DeepEqual(users, expected) {
+		t.Errorf("expected %v, got %v", expected, users)
+	}
+
+	if stub.queries[0] != `SELECT * FROM "users" WHERE "id" > $1` || !reflect.DeepEqual(stub.args[0], []driver.Value{int64(0)}) {
+		t.Errorf("unexpected query %q %v", stub.queries[0], stub.args[0])
+	}
+
+	var pointers []*stubUser
+	if err := executor.Select(context.Background(), &pointers, query, "users", ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(pointers) != 2 || *pointers[1] != expected[1] {
+		t.Errorf("expected pointers of %v, got %v", expected, pointers)
+	}
+
+	var maps []map[string]interface{}
+	if err := executor.Select(context.Background(), &maps, query, "users", ""); err != nil {
+		t.Fatal(err)
+	}
+	if maps[0]["name"] != "alice" || maps[1]["id"] != int64(2) {
+		t.Errorf("unexpected maps %v", maps)
+	}
+
+	if err := executor.Select(context.Background(), users, query, "users", ""); err == nil {
+		t.Error("expected error of non pointer destination")
+	}
+}
+
+func TestExecutorGet(t *testing.T) {
+	executor := newStubExecutor(t, map[string]*stubRows{
+		`SELECT "name"`: {columns: []string{"name"}, data: [][]driver.Value{{[]byte("alice")}}},
+		`SELECT "id"`:   {columns: []string{"id"}},
+		"SELECT":        stubUserRows,
+	})
+
+	var user stubUser
+	if err := executor.Get(context.Background(), &user, NewQueryBuilder(WithDialect(PostgreSQL)), "users", ""); err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != 1 || user.Name != "alice" || user.CreatedBy != "admin" {
+		t.Errorf("unexpected user %v", user)
+	}
+
+	var data map[string]interface{}
+	if err := executor.Get(context.Background(), &data, NewQueryBuilder(WithDialect(PostgreSQL)), "users", ""); err != nil {
+		t.Fatal(err)
+	}
+	if data["created_by"] != "admin" {
+		t.Errorf("unexpected map %v", data)
+	}
+
+	var name string
+	query := NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddSelection("name")
+	if err := executor.Get(context.Background(), &name, query, "users", ""); err != nil {
+		t.Fatal(err)
+	}
+	if name != "alice" {
+		t.Errorf("expected alice, got %q", name)
+	}
+
+	var id int64
+	query = NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddSelection("id")
+	if err := executor.Get(context.Background(), &id, query, "users", ""); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+
+	if err := executor.Get(context.Background(), &id, NewQueryBuilder(WithDialect(PostgreSQL)), "users", ""); err == nil {
+		t.Error("expected error of single value destination for multiple columns")
+	}
+}
+
+func TestExecutorCount(t *testing.T) {
+	executor := newStubExecutor(t, map[string]*stubRows{
+		"SELECT COUNT(*)": {columns: []string{"count"}, data: [][]driver.Value{{int64(42)}}},
+	})
+
+	query := NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddWhere("status", "", "paid")
+	query.AddSort(DirDesc, "id")
+	query.AddPagination(NewPagination(2, 10))
+
+	count, err := executor.Count(context.Background(), query, "orders", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 42 {
+		t.Errorf("expected 42, got %d", count)
+	}
+
+	if stub.queries[0] != `SELECT COUNT(*) FROM "orders" WHERE "status" = $1` {
+		t.Errorf("unexpected query %q", stub.queries[0])
+	}
+}
@@ -0,0 +1,219 @@
+package goutils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Muruyung/go-utilities/converter"
+)
+
+// =================================================
+
+// filterType type of filter value
+type filterType struct {
+	kind string
+}
+
+var (
+	// FilterString string filter value
+	FilterString = filterType{
+		kind: "string",
+	}
+
+	// FilterInt integer filter value
+	FilterInt = filterType{
+		kind: "int",
+	}
+
+	// FilterFloat float filter value
+	FilterFloat = filterType{
+		kind: "float",
+	}
+
+	// FilterBool boolean filter value
+	FilterBool = filterType{
+		kind: "bool",
+	}
+
+	// FilterTime date time filter value, parsed with converter.ConvertStringToDate
+	FilterTime = filterType{
+		kind: "time",
+	}
+
+	comparisonOperations = []string{"eq", "neq", "is", "is_not", "lt", "lte", "gt", "gte", "in", "not_in", "between", "not_between"}
+
+	boolOperations = []string{"eq", "neq", "is", "is_not"}
+)
+
+// FilterField filterable field, equality and comparison operations are allowed when Operations is empty,
+// pattern operations such as like, substring and regexp must be listed in Operations
+type FilterField struct {
+	Column     string
+	Type       filterType
+	Operations []string
+}
+
+// FilterableFields registry of filterable fields, map public api name to field
+type FilterableFields map[string]FilterField
+
+// FilterError error of filter which is unknown or has invalid operation or value
+type FilterError struct {
+	Field     string
+	Operation string
+	Value     string
+	Message   string
+}
+
+// Error error message of filter
+func (err *FilterError) Error() string {
+	return fmt.Sprintf("invalid filter %s %s %q: %s", err.Field, err.Operation, err.Value, err.Message)
+}
+
+type filter struct {
+	column    string
+	operation string
+	value     interface{}
+}
+
+// =================================================
+
+// SetFilterableFields set filterable fields registry, every filter must be registered
+func (request *RequestOption) SetFilterableFields(fields FilterableFields) *RequestOption {
+	request.filterableFields = fields
+	return request
+}
+
+// SetFilter set filter request, value is converted to the type of filterable field,
+// list value of in and between is comma separated and null is converted to nil
+func (request *RequestOption) SetFilter(field string, operation string, value string) (*RequestOption, error) {
+	if operation == "" {
+		operation = "eq"
+	}
+
+	filterField, ok := request.filterableFields[field]
+	if !ok {
+		return nil, &FilterError{
+			Field:     field,
+			Operation: operation,
+			Value:     value,
+			Message:   "unknown filter field",
+		}
+	}
+
+	if !isAllowedOperation(filterField, operation) {
+		return nil, &FilterError{
+			Field:     field,
+			Operation: operation,
+			Value:     value,
+			Message:   "operation is not allowed",
+		}
+	}
+
+	converted, err := convertFilterValue(filterField.Type, operation, value)
+	if err != nil {
+		return nil, &FilterError{
+			Field:     field,
+			Operation: operation,
+			Value:     value,
+			Message:   err.Error(),
+		}
+	}
+
+	request.filters = append(request.filters, filter{
+		column:    filterField.Column,
+		operation: operation,
+		value:     converted,
+	})
+	return request, nil
+}
+
+// SetFilterWhere set filter request into where of query builder
+func (request *RequestOption) SetFilterWhere(query QueryBuilderInteractor) QueryBuilderInteractor {
+	for _, val := range request.filters {
+		query.AddWhere(val.column, val.operation, val.value)
+	}
+
+	return query
+}
+
+func isAllowedOperation(field FilterField, operation string) bool {
+	operations := field.Operations
+	if len(operations) == 0 {
+		switch field.Type {
+		case FilterBool:
+			operations = boolOperations
+		default:
+			operations = comparisonOperations
+		}
+	}
+
+	for _, val := range operations {
+		if val == operation {
+			return true
+		}
+	}
+	return false
+}
+
+func convertFilterValue(kind filterType, operation string, value string) (interface{}, error) {
+	if value == "null" {
+		switch operation {
+		case "eq", "neq", "is", "is_not":
+			return nil, nil
+		}
+	}
+
+	if !isListOperation(operation) {
+		return convertFilterItem(kind, value)
+	}
+
+	items := strings.Split(value, ",")
+	if (operation == "between" || operation == "not_between") && len(items) != 2 {
+		return nil, fmt.Errorf("%s requires two comma separated values", operation)
+	}
+
+	values := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		converted, err := convertFilterItem(kind, strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, converted)
+	}
+	return values, nil
+}
+
+func convertFilterItem(kind filterType, value string) (interface{}, error) {
+	switch kind {
+	case FilterInt:
+		converted, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, errors.New("must be an integer")
+		}
+		return converted, nil
+	case FilterFloat:
+		converted, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errors.New("must be a number")
+		}
+		return converted, nil
+	case FilterBool:
+		converted, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("must be a boolean")
+		}
+		return converted, nil
+	case FilterTime:
+		converted, err := converter.ConvertStringToDate(value)
+		if err != nil {
+			return nil, errors.New("must be a date time")
+		}
+		return converted, nil
+	case FilterString:
+		return value, nil
+	}
+
+	return nil, errors.New("invalid filter type")
+}
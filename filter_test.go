@@ -0,0 +1,29 @@
+package goutils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFilterPatternOperationIsOptIn(t *testing.T) {
+	request := NewRequestOption().SetFilterableFields(FilterableFields{
+		"name":  {Column: "name", Type: FilterString},
+		"email": {Column: "email", Type: FilterString, Operations: []string{"eq", "i_substring"}},
+	})
+
+	for _, operation := range []string{"regexp", "like", "i_substring"} {
+		_, err := request.SetFilter("name", operation, "a.*")
+		var filterErr *FilterError
+		if !errors.As(err, &filterErr) {
+			t.Errorf("%s: expected FilterError, got %v", operation, err)
+		}
+	}
+
+	if _, err := request.SetFilter("name", "gte", "a"); err != nil {
+		t.Errorf("gte: %v", err)
+	}
+
+	if _, err := request.SetFilter("email", "i_substring", "gmail"); err != nil {
+		t.Errorf("i_substring: %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package goutils
+
+import (
+	"errors"
+	"strings"
+)
+
+// =================================================
+
+// QueryErrors list of error which is collected by fluent query builder methods
+type QueryErrors []error
+
+// Error error message of collected errors
+func (errs QueryErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Is whether one of collected errors matches target, used by errors.Is
+func (errs QueryErrors) Is(target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As find the first collected error which matches target, used by errors.As
+func (errs QueryErrors) As(target interface{}) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// =================================================
+
+// FluentQueryBuilder chainable query builder, every method return the builder and invalid input
+// is collected as error and returned from GetQuery
+type FluentQueryBuilder struct {
+	query *queryBuilder
+}
+
+// NewFluentQueryBuilder create new fluent query builder
+func NewFluentQueryBuilder(opts ...QueryBuilderOption) *FluentQueryBuilder {
+	return &FluentQueryBuilder{
+		query: NewQueryBuilder(opts...).(*queryBuilder),
+	}
+}
+
+// Builder query builder of fluent query builder, e.g. to be used with executor or Subquery,
+// collected errors are returned from its GetQuery
+func (f *FluentQueryBuilder) Builder() QueryBuilderInteractor {
+	return f.query
+}
+
+// GetQuery parse query, collected errors are returned as QueryErrors
+func (f *FluentQueryBuilder) GetQuery(tablename string, aliases string) (query string, values []interface{}, err error) {
+	return f.query.GetQuery(tablename, aliases)
+}
+
+// GetCountQuery parse count query, collected errors are returned as QueryErrors
+func (f *FluentQueryBuilder) GetCountQuery(tablename string, aliases string) (query string, values []interface{}, err error) {
+	return f.query.GetCountQuery(tablename, aliases)
+}
+
+// Select add selection, invalid column is collected as error and returned from GetQuery
+func (f *FluentQueryBuilder) Select(selection ...string) *FluentQueryBuilder {
+	q := f.query
+	for _, val := range selection {
+		if _, _, err := parseSelection(q.option.dialect, []expression{identifierExpression(val)}); err != nil {
+			q.addError(err)
+			continue
+		}
+
+		q.AddSelection(val)
+	}
+	return f
+}
+
+// Join add join table, invalid table or condition is collected as error and returned from GetQuery
+func (f *FluentQueryBuilder) Join(joinType joinType, tableName string, aliases string, on string) *FluentQueryBuilder {
+	q := f.query
+	if _, _, err := parseJoin(q.option.dialect, join{
+		joinType:  joinType.join,
+		tableName: tableName,
+		aliases:   aliases,
+		on:        on,
+	}); err != nil {
+		q.addError(err)
+		return f
+	}
+
+	q.AddJoin(joinType, tableName, aliases, on)
+	return f
+}
+
+// Where add where query, invalid attribute, operation or value is collected as error and returned from GetQuery
+func (f *FluentQueryBuilder) Where(attribute string, operation string, value interface{}) *FluentQueryBuilder {
+	q := f.query
+	if _, _, err := parseWhere(q.option.dialect, buildWhere(attribute, operation, value)); err != nil {
+		q.addError(prependPath(err, "where"))
+		return f
+	}
+
+	q.AddWhere(attribute, operation, value)
+	return f
+}
+
+// WhereCondition add typed where condition, invalid condition is collected as error and returned from GetQuery
+func (f *FluentQueryBuilder) WhereCondition(condition ...Condition) *FluentQueryBuilder {
+	q := f.query
+	for _, val := range condition {
+		if val == nil {
+			q.addError(newQueryError(ErrInvalidValue, "", RawKey, nil))
+			continue
+		}
+
+		if _, _, err := val.parse(q.option.dialect); err != nil {
+			q.addError(prependPath(err, "where"))
+			continue
+		}
+
+		q.AddCondition(val)
+	}
+	return f
+}
+
+// GroupBy add group query, invalid column is collected as error and returned from GetQuery
+func (f *FluentQueryBuilder) GroupBy(group ...string) *FluentQueryBuilder {
+	q := f.query
+	if _, err := parseGroup(q.option.dialect, group); err != nil {
+		q.addError(err)
+		return f
+	}
+
+	q.AddGroup(group...)
+	return f
+}
+
+// Having add having query, invalid attribute, operation or value is collected as error and returned from GetQuery
+func (f *FluentQueryBuilder) Having(attribute string, operation string, value interface{}) *FluentQueryBuilder {
+	q := f.query
+	if _, _, err := parseWhere(q.option.dialect, buildWhere(attribute, operation, value)); err != nil {
+		q.addError(prependPath(err, "having"))
+		return f
+	}
+
+	q.AddHaving(attribute, operation, value)
+	return f
+}
+
+// OrderBy add sort query, invalid direction or column is collected as error and returned from GetQuery
+func (f *FluentQueryBuilder) OrderBy(direction direction, sortBy ...string) *FluentQueryBuilder {
+	q := f.query
+	sorts := make([]sortItem, 0, len(sortBy))
+	for _, val := range sortBy {
+		sorts = append(sorts, sortItem{
+			column:    val,
+			direction: direction,
+		})
+	}
+
+	if _, err := parseSort(q.option.dialect, sorts); err != nil {
+		q.addError(err)
+		return f
+	}
+
+	q.AddSort(direction, sortBy...)
+	return f
+}
+
+// Page add pagination query, page and limit must be positive, otherwise it is collected as error and returned from GetQuery
+func (f *FluentQueryBuilder) Page(page int, limit int) *FluentQueryBuilder {
+	q := f.query
+	if page <= 0 {
+		q.addError(newQueryError(ErrInvalidValue, "page", "", page))
+		return f
+	}
+
+	if limit <= 0 {
+		q.addError(newQueryError(ErrInvalidValue, "limit", "", limit))
+		return f
+	}
+
+	q.AddPagination(NewPagination(page, limit))
+	return f
+}
+
+func (q *queryBuilder) addError(err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.errs = append(q.errs, err)
+}
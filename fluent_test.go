@@ -0,0 +1,50 @@
+package goutils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFluentQueryBuilder(t *testing.T) {
+	res, values, err := NewFluentQueryBuilder(WithDialect(PostgreSQL)).
+		Select("o.id", "COUNT(*) AS total").
+		Join(LeftJoin, "users", "u", "u.id = o.user_id").
+		Where("o.status", "", "paid").
+		WhereCondition(Gt("o.amount", 10)).
+		GroupBy("o.id").
+		Having("o.id", "gt", 1).
+		OrderBy(DirDesc, "o.id").
+		Page(2, 20).
+		GetQuery("orders", "o")
+
+	assertQuery(t, "fluent", res, values, err,
+		`SELECT "o"."id", COUNT(*) "total" FROM "orders" "o" LEFT JOIN "users" "u" ON "u"."id" = "o"."user_id" `+
+			`WHERE ("o"."status" = $1 AND "o"."amount" > $2) GROUP BY "o"."id" HAVING "o"."id" > $3 ORDER BY "o"."id" DESC LIMIT 20 OFFSET 20`,
+		[]interface{}{"paid", 10, 1},
+	)
+}
+
+func TestFluentQueryBuilderCollectErrors(t *testing.T) {
+	query := NewFluentQueryBuilder().
+		Select("id; drop").
+		Where("a", "bogus", 1).
+		Page(0, 10)
+
+	for _, get := range []func(string, string) (string, []interface{}, error){query.GetQuery, query.Builder().GetQuery} {
+		_, _, err := get("users", "")
+
+		var errs QueryErrors
+		if !errors.As(err, &errs) || len(errs) != 3 {
+			t.Fatalf("expected 3 collected errors, got %v", err)
+		}
+
+		if !errors.Is(err, ErrInvalidIdentifier) || !errors.Is(err, ErrInvalidOperator) || !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("expected every sentinel error to match, got %v", err)
+		}
+
+		var queryErr *QueryError
+		if !errors.As(err, &queryErr) || queryErr.Attribute != "id; drop" {
+			t.Errorf("expected first QueryError, got %v", queryErr)
+		}
+	}
+}
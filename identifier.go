@@ -0,0 +1,143 @@
+package goutils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// =================================================
+
+var (
+	namePattern          = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	columnPattern        = regexp.MustCompile(`^(\*|[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*(\.\*)?)$`)
+	aggregatePattern     = regexp.MustCompile(`^(?i)(COUNT|SUM|AVG|MIN|MAX)\(\s*(DISTINCT\s+)?([^()\s]+)\s*\)$`)
+	aggregateCallPattern = regexp.MustCompile(`^\s*(?i)(COUNT|SUM|AVG|MIN|MAX|STRING_AGG|GROUP_CONCAT|ARRAY_AGG)\s*\(`)
+	distinctPattern      = regexp.MustCompile(`^\s*(?i)DISTINCT\s`)
+	aliasPattern         = regexp.MustCompile(`^(.+?)\s+(?i:AS\s+)?([A-Za-z_][A-Za-z0-9_]*)$`)
+	onPattern            = regexp.MustCompile(`^(\S+)\s*(=|!=|<>|<=|>=|<|>)\s*(\S+)$`)
+	andPattern           = regexp.MustCompile(`(?i)\s+AND\s+`)
+
+	// keywords sql keywords which can not be column or aliases of "column aliases" selection,
+	// so keyword led selection such as "DISTINCT name" is rejected instead of read as column with aliases
+	keywords = map[string]bool{
+		"ALL": true, "AND": true, "AS": true, "BETWEEN": true, "BY": true, "CASE": true, "CAST": true,
+		"DISTINCT": true, "ELSE": true, "END": true, "EXISTS": true, "FROM": true, "GROUP": true,
+		"HAVING": true, "IN": true, "INTERVAL": true, "IS": true, "JOIN": true, "LIKE": true, "LIMIT": true,
+		"NOT": true, "NULL": true, "OFFSET": true, "ON": true, "OR": true, "ORDER": true, "SELECT": true,
+		"THEN": true, "TOP": true, "UNION": true, "WHEN": true, "WHERE": true,
+	}
+)
+
+// identifier validate and quote identifier: table, table.column or aliases
+func (d dialect) identifier(name string) (string, error) {
+	if !identifierPattern.MatchString(name) {
+		return "", newQueryError(ErrInvalidIdentifier, name, "", nil)
+	}
+
+	return d.quoteIdentifier(name), nil
+}
+
+// column validate and quote column expression: *, column, table.column, table.*
+// or aggregate of column such as COUNT(*), SUM(amount) and COUNT(DISTINCT id), optionally followed by aliases.
+// Keyword led expression such as DISTINCT name is rejected, use Raw for it
+func (d dialect) column(expr string, allowAliases bool) (string, error) {
+	expr = strings.TrimSpace(expr)
+	if allowAliases {
+		if match := aliasPattern.FindStringSubmatch(expr); match != nil && !isKeyword(match[1]) && !isKeyword(match[2]) {
+			if column, err := d.column(match[1], false); err == nil {
+				return fmt.Sprintf("%s %s", column, d.quoteIdentifier(match[2])), nil
+			}
+		}
+	}
+
+	if columnPattern.MatchString(expr) {
+		return d.quoteColumn(expr), nil
+	}
+
+	if match := aggregatePattern.FindStringSubmatch(expr); match != nil && columnPattern.MatchString(match[3]) {
+		distinct := ""
+		if match[2] != "" {
+			distinct = "DISTINCT "
+		}
+		return fmt.Sprintf("%s(%s%s)", strings.ToUpper(match[1]), distinct, d.quoteColumn(match[3])), nil
+	}
+
+	return "", newQueryError(ErrInvalidIdentifier, expr, "", nil)
+}
+
+// isKeyword word is sql keyword
+func isKeyword(word string) bool {
+	return keywords[strings.ToUpper(word)]
+}
+
+// quoteColumn quote column which is already validated, * is kept as is
+func (d dialect) quoteColumn(column string) string {
+	if column == "*" {
+		return column
+	}
+
+	if strings.HasSuffix(column, ".*") {
+		return d.quoteIdentifier(strings.TrimSuffix(column, ".*")) + ".*"
+	}
+
+	return d.quoteIdentifier(column)
+}
+
+// parseOn validate join condition, column = column joined by AND, use AddRawJoin for sql expression
+func (d dialect) parseOn(on string) (query string, err error) {
+	conditions := andPattern.Split(strings.TrimSpace(on), -1)
+	for key, val := range conditions {
+		match := onPattern.FindStringSubmatch(val)
+		if match == nil {
+			err = prependPath(newQueryError(ErrInvalidIdentifier, val, "", nil), "on")
+			return
+		}
+
+		var left, right string
+		if left, err = d.identifier(match[1]); err != nil {
+			return query, prependPath(err, "on")
+		}
+		if right, err = d.identifier(match[3]); err != nil {
+			return query, prependPath(err, "on")
+		}
+		conditions[key] = fmt.Sprintf("%s %s %s", left, match[2], right)
+	}
+
+	query = strings.Join(conditions, " AND ")
+	return
+}
+
+// =================================================
+
+// identifierExpression column expression validated and quoted on parse
+type identifierExpression string
+
+func (i identifierExpression) parse(d dialect) (query string, values []interface{}, err error) {
+	query, err = d.column(string(i), true)
+	return
+}
+
+// rawExpression raw sql expression with ? placeholder, written as is without validation
+type rawExpression struct {
+	query  string
+	values []interface{}
+}
+
+// Raw create raw sql expression with ? placeholder, only use it with trusted sql.
+// Literal question mark such as postgres jsonb operator ?, ?| and ?& is escaped as ??, e.g. Raw("tags ?? ?", "x")
+func Raw(query string, values ...interface{}) rawExpression {
+	return rawExpression{
+		query:  query,
+		values: values,
+	}
+}
+
+func (r rawExpression) parse(d dialect) (query string, values []interface{}, err error) {
+	return r.query, r.values, nil
+}
+
+// String raw expression cache key
+func (r rawExpression) String() string {
+	return fmt.Sprintf("%s%v", r.query, r.values)
+}
@@ -0,0 +1,75 @@
+package goutils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelectionKeywordIsRejected(t *testing.T) {
+	for _, d := range []dialect{defaultDialect, PostgreSQL, MySQL, SQLite} {
+		for _, selection := range []string{"DISTINCT name", "distinct name", "name FROM", "SELECT name"} {
+			query := NewQueryBuilder(WithDialect(d))
+			query.AddSelection(selection)
+
+			res, _, err := query.GetQuery("users", "")
+			if !errors.Is(err, ErrInvalidIdentifier) {
+				t.Errorf("%s %q: expected ErrInvalidIdentifier, got %q %v", d.name, selection, res, err)
+			}
+		}
+	}
+}
+
+func TestSelectionRawDistinct(t *testing.T) {
+	query := NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddRawSelection(Raw("DISTINCT name"))
+
+	res, _, err := query.GetQuery("users", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := `SELECT DISTINCT name FROM "users"`; res != expected {
+		t.Errorf("expected %q, got %q", expected, res)
+	}
+}
+
+func TestSelectionAliases(t *testing.T) {
+	tests := map[string]string{
+		"name n":                 `SELECT "name" "n" FROM "users"`,
+		"name AS n":              `SELECT "name" "n" FROM "users"`,
+		"u.name":                 `SELECT "u"."name" FROM "users"`,
+		"COUNT(DISTINCT id) cnt": `SELECT COUNT(DISTINCT "id") "cnt" FROM "users"`,
+	}
+
+	for selection, expected := range tests {
+		query := NewQueryBuilder(WithDialect(PostgreSQL))
+		query.AddSelection(selection)
+
+		res, _, err := query.GetQuery("users", "")
+		if err != nil {
+			t.Errorf("%q: %v", selection, err)
+			continue
+		}
+
+		if res != expected {
+			t.Errorf("%q: expected %q, got %q", selection, expected, res)
+		}
+	}
+}
+
+func TestRawEscapedQuestionMark(t *testing.T) {
+	tests := map[dialect]string{
+		PostgreSQL: `SELECT * FROM "posts" WHERE ((tags ? 'x' AND "id" = $1) AND tags ?| $2)`,
+		MySQL:      "SELECT * FROM `posts` WHERE ((tags ? 'x' AND `id` = ?) AND tags ?| ?)",
+	}
+
+	for d, expected := range tests {
+		query := NewQueryBuilder(WithDialect(d))
+		query.AddWhereExpression(Raw("tags ?? 'x'"))
+		query.AddWhere("id", "", 1)
+		query.AddWhereExpression(Raw("tags ??| ?", "y"))
+
+		res, values, err := query.GetQuery("posts", "")
+		assertQuery(t, d.name, res, values, err, expected, []interface{}{1, "y"})
+	}
+}
@@ -0,0 +1,342 @@
+package goutils
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Muruyung/go-utilities/converter"
+)
+
+// =================================================
+
+// InsertBuilderInteractor insert query builder interactor
+type InsertBuilderInteractor interface {
+	GetQuery(tablename string) (query string, values []interface{}, err error)
+	AddRow(row ...interface{})
+	AddReturning(column ...string)
+	AddOnConflictUpdate(conflictColumns []string, updateColumns ...string)
+	AddOnConflictSetExpression(column string, expression rawExpression)
+	AddOnConflictDoNothing(conflictColumns ...string)
+	Excluded(column string) string
+}
+
+type insertBuilder struct {
+	rows      *[]interface{}
+	returning *[]string
+	conflict  *onConflict
+	option    builderOption
+}
+
+type onConflict struct {
+	columns   []string
+	doNothing bool
+	set       *[]assignment
+}
+
+// NewInsertBuilder create new insert query builder
+func NewInsertBuilder(opts ...QueryBuilderOption) InsertBuilderInteractor {
+	return &insertBuilder{
+		rows:      nil,
+		returning: nil,
+		option:    newBuilderOption(opts...),
+	}
+}
+
+// GetQuery parse insert query
+func (q *insertBuilder) GetQuery(tablename string) (query string, values []interface{}, err error) {
+	if q.rows == nil {
+		err = prependPath(newQueryError(ErrInvalidValue, tablename, "", nil), "rows")
+		return
+	}
+
+	var (
+		d       = q.option.dialect
+		columns []string
+		rows    []string
+	)
+	for key, val := range *q.rows {
+		var row map[string]interface{}
+		row, err = parseDataRow(d, val)
+		if err != nil {
+			return "", nil, prependPath(err, "rows", strconv.Itoa(key))
+		}
+
+		if key == 0 {
+			columns = sortedKeys(row)
+		} else if !isSameColumns(columns, row) {
+			err = prependPath(newQueryError(ErrInvalidValue, "", "", sortedKeys(row)), "rows", strconv.Itoa(key))
+			return "", nil, err
+		}
+
+		placeholders := make([]string, 0, len(columns))
+		for _, column := range columns {
+			placeholders = append(placeholders, "?")
+			values = append(values, row[column])
+		}
+		rows = append(rows, fmt.Sprintf("(%s)", strings.Join(placeholders, ", ")))
+	}
+
+	var table, insertColumns string
+	if table, err = d.identifier(tablename); err != nil {
+		return "", nil, err
+	}
+
+	if insertColumns, err = parseColumns(d, columns); err != nil {
+		return "", nil, prependPath(err, "columns")
+	}
+
+	query = fmt.Sprintf(`INSERT INTO %s (%s) VALUES %s`,
+		table, insertColumns, strings.Join(rows, ", "),
+	)
+
+	if q.conflict != nil {
+		var (
+			conflict       string
+			conflictValues []interface{}
+		)
+		conflict, conflictValues, err = q.parseOnConflict(columns)
+		if err != nil {
+			return "", nil, err
+		}
+		query = fmt.Sprintf(`%s %s`, query, conflict)
+		values = append(values, conflictValues...)
+	}
+
+	if q.returning != nil {
+		if !d.supportReturning() {
+			err = newQueryError(ErrInvalidOperator, "RETURNING", "", d.name)
+			return "", nil, err
+		}
+
+		returning := make([]string, 0, len(*q.returning))
+		for _, val := range *q.returning {
+			var column string
+			if column, err = d.column(val, true); err != nil {
+				return "", nil, prependPath(err, "returning")
+			}
+			returning = append(returning, column)
+		}
+		query = fmt.Sprintf(`%s RETURNING %s`, query, strings.Join(returning, ", "))
+	}
+
+	query = d.rebind(query)
+	return
+}
+
+// =================================================
+
+// AddRow add row to insert, row is a struct or map[string]interface{}
+func (q *insertBuilder) AddRow(row ...interface{}) {
+	arrRow := make([]interface{}, 0)
+	if q.rows != nil {
+		arrRow = append(arrRow, *q.rows...)
+	}
+
+	arrRow = append(arrRow, row...)
+	q.rows = &arrRow
+}
+
+// parseDataRow convert struct or map[string]interface{} into map of column value,
+// struct column is read from db tag as the executor does and value is kept as is
+func parseDataRow(d dialect, data interface{}) (row map[string]interface{}, err error) {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		row = make(map[string]interface{})
+		for key, val := range value {
+			row[key] = val
+		}
+	default:
+		row = structRow(data)
+	}
+
+	if len(row) == 0 {
+		err = newQueryError(ErrInvalidValue, "", "", data)
+		return
+	}
+
+	if d == MySQL {
+		row = converter.ConvertTypeToStdMysqlType(row)
+	}
+	return
+}
+
+// structRow map column of db tag into field value of struct or pointer of struct
+func structRow(data interface{}) map[string]interface{} {
+	value := reflect.ValueOf(data)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	row := make(map[string]interface{})
+	for column, index := range structFields(value.Type()) {
+		row[column] = value.FieldByIndex(index).Interface()
+	}
+	return row
+}
+
+func isSameColumns(columns []string, row map[string]interface{}) bool {
+	if len(columns) != len(row) {
+		return false
+	}
+
+	for _, column := range columns {
+		if _, ok := row[column]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func parseColumns(d dialect, columns []string) (query string, err error) {
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column, err = d.identifier(column); err != nil {
+			return
+		}
+		quoted = append(quoted, column)
+	}
+
+	query = strings.Join(quoted, ", ")
+	return
+}
+
+// =================================================
+
+// AddReturning add returning columns, not supported by mysql
+func (q *insertBuilder) AddReturning(column ...string) {
+	arrReturning := make([]string, 0)
+	if q.returning != nil {
+		arrReturning = append(arrReturning, *q.returning...)
+	}
+
+	arrReturning = append(arrReturning, column...)
+	q.returning = &arrReturning
+}
+
+// =================================================
+
+// AddOnConflictUpdate update columns with the inserted row on conflict,
+// every inserted column except conflict columns is updated when updateColumns is empty
+func (q *insertBuilder) AddOnConflictUpdate(conflictColumns []string, updateColumns ...string) {
+	q.conflict = &onConflict{
+		columns: conflictColumns,
+	}
+
+	for _, column := range updateColumns {
+		q.AddOnConflictSetExpression(column, Raw(q.Excluded(column)))
+	}
+}
+
+// AddOnConflictSetExpression add set column with raw sql expression on conflict,
+// use Excluded to refer the inserted row, e.g. Raw("count + " + query.Excluded("count"))
+func (q *insertBuilder) AddOnConflictSetExpression(column string, expression rawExpression) {
+	if q.conflict == nil || q.conflict.doNothing {
+		q.conflict = &onConflict{}
+	}
+
+	q.conflict.set = appendAssignment(q.conflict.set, assignment{
+		column:     column,
+		expression: expression.query,
+		values:     expression.values,
+	})
+}
+
+// AddOnConflictDoNothing ignore inserted row on conflict
+func (q *insertBuilder) AddOnConflictDoNothing(conflictColumns ...string) {
+	q.conflict = &onConflict{
+		columns:   conflictColumns,
+		doNothing: true,
+	}
+}
+
+// Excluded refer column of the inserted row on conflict, EXCLUDED.column or VALUES(column) for mysql
+func (q *insertBuilder) Excluded(column string) string {
+	d := q.option.dialect
+	if d == MySQL {
+		return fmt.Sprintf("VALUES(%s)", d.quoteIdentifier(column))
+	}
+
+	return fmt.Sprintf("EXCLUDED.%s", d.quoteIdentifier(column))
+}
+
+func (q *insertBuilder) parseOnConflict(columns []string) (query string, values []interface{}, err error) {
+	var (
+		d        = q.option.dialect
+		conflict = *q.conflict
+		set      []assignment
+	)
+
+	if conflict.doNothing && d == MySQL {
+		set = []assignment{{
+			column:     columns[0],
+			expression: d.quoteIdentifier(columns[0]),
+		}}
+	} else if !conflict.doNothing {
+		if conflict.set != nil {
+			set = *conflict.set
+		} else {
+			for _, column := range columns {
+				if !isConflictColumn(conflict.columns, column) {
+					set = append(set, assignment{
+						column:     column,
+						expression: q.Excluded(column),
+					})
+				}
+			}
+		}
+
+		if len(set) == 0 {
+			err = prependPath(newQueryError(ErrInvalidValue, "", "", nil), "on_conflict", "set")
+			return
+		}
+	}
+
+	if d == MySQL {
+		query, values, err = parseAssignment(d, set)
+		if err != nil {
+			return "", nil, prependPath(err, "on_conflict")
+		}
+		query = fmt.Sprintf(`ON DUPLICATE KEY UPDATE %s`, query)
+		return
+	}
+
+	query = `ON CONFLICT`
+	if len(conflict.columns) > 0 {
+		var conflictColumns string
+		if conflictColumns, err = parseColumns(d, conflict.columns); err != nil {
+			return "", nil, prependPath(err, "on_conflict", "columns")
+		}
+		query = fmt.Sprintf(`%s (%s)`, query, conflictColumns)
+	} else if !conflict.doNothing {
+		err = prependPath(newQueryError(ErrInvalidValue, "", "", nil), "on_conflict", "columns")
+		return
+	}
+
+	if conflict.doNothing {
+		query = fmt.Sprintf(`%s DO NOTHING`, query)
+		return
+	}
+
+	var update string
+	update, values, err = parseAssignment(d, set)
+	if err != nil {
+		return "", nil, prependPath(err, "on_conflict")
+	}
+	query = fmt.Sprintf(`%s DO UPDATE SET %s`, query, update)
+	return
+}
+
+func isConflictColumn(conflictColumns []string, column string) bool {
+	for _, val := range conflictColumns {
+		if val == column {
+			return true
+		}
+	}
+	return false
+}
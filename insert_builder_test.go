@@ -0,0 +1,149 @@
+package goutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Muruyung/go-utilities/converter"
+)
+
+type insertRow struct {
+	ID        int64     `db:"id" json:"identifier"`
+	Active    bool      `db:"active"`
+	CreatedAt time.Time `db:"created_at"`
+	Ignored   string    `db:"-"`
+}
+
+func TestInsertStructRowKeepsNativeValue(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	row := insertRow{ID: 9007199254740993, Active: true, CreatedAt: createdAt, Ignored: "x"}
+
+	query := NewInsertBuilder(WithDialect(PostgreSQL))
+	query.AddRow(row)
+
+	res, values, err := query.GetQuery("users")
+	assertQuery(t, "postgres", res, values, err,
+		`INSERT INTO "users" ("active", "created_at", "id") VALUES ($1, $2, $3)`,
+		[]interface{}{true, createdAt, int64(9007199254740993)},
+	)
+
+	query = NewInsertBuilder(WithDialect(MySQL))
+	query.AddRow(&row)
+
+	res, values, err = query.GetQuery("users")
+	assertQuery(t, "mysql", res, values, err,
+		"INSERT INTO `users` (`active`, `created_at`, `id`) VALUES (?, ?, ?)",
+		[]interface{}{1, converter.ConvertDateToString(createdAt), int64(9007199254740993)},
+	)
+}
+
+func TestInsertOnConflict(t *testing.T) {
+	row := map[string]interface{}{"id": 1, "name": "john", "email": "john@mail.com"}
+	tests := []struct {
+		name     string
+		dialect  dialect
+		build    func(query InsertBuilderInteractor)
+		expected string
+		values   []interface{}
+	}{
+		{
+			name:    "postgres update every column",
+			dialect: PostgreSQL,
+			build: func(query InsertBuilderInteractor) {
+				query.AddOnConflictUpdate([]string{"id"})
+			},
+			expected: `INSERT INTO "users" ("email", "id", "name") VALUES ($1, $2, $3) ON CONFLICT ("id") DO UPDATE SET "email" = EXCLUDED."email", "name" = EXCLUDED."name"`,
+			values:   []interface{}{"john@mail.com", 1, "john"},
+		},
+		{
+			name:    "postgres update columns",
+			dialect: PostgreSQL,
+			build: func(query InsertBuilderInteractor) {
+				query.AddOnConflictUpdate([]string{"id"}, "name")
+			},
+			expected: `INSERT INTO "users" ("email", "id", "name") VALUES ($1, $2, $3) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+			values:   []interface{}{"john@mail.com", 1, "john"},
+		},
+		{
+			name:    "postgres set expression with value",
+			dialect: PostgreSQL,
+			build: func(query InsertBuilderInteractor) {
+				query.AddOnConflictUpdate([]string{"id"}, "name")
+				query.AddOnConflictSetExpression("visit", Raw(`"users"."visit" + ?`, 1))
+				query.AddReturning("id")
+			},
+			expected: `INSERT INTO "users" ("email", "id", "name") VALUES ($1, $2, $3) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name", "visit" = "users"."visit" + $4 RETURNING "id"`,
+			values:   []interface{}{"john@mail.com", 1, "john", 1},
+		},
+		{
+			name:    "postgres do nothing",
+			dialect: PostgreSQL,
+			build: func(query InsertBuilderInteractor) {
+				query.AddOnConflictDoNothing("id")
+			},
+			expected: `INSERT INTO "users" ("email", "id", "name") VALUES ($1, $2, $3) ON CONFLICT ("id") DO NOTHING`,
+			values:   []interface{}{"john@mail.com", 1, "john"},
+		},
+		{
+			name:    "sqlite do nothing without conflict columns",
+			dialect: SQLite,
+			build: func(query InsertBuilderInteractor) {
+				query.AddOnConflictDoNothing()
+			},
+			expected: `INSERT INTO "users" ("email", "id", "name") VALUES (?, ?, ?) ON CONFLICT DO NOTHING`,
+			values:   []interface{}{"john@mail.com", 1, "john"},
+		},
+		{
+			name:    "mysql update every column",
+			dialect: MySQL,
+			build: func(query InsertBuilderInteractor) {
+				query.AddOnConflictUpdate([]string{"id"})
+			},
+			expected: "INSERT INTO `users` (`email`, `id`, `name`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `email` = VALUES(`email`), `name` = VALUES(`name`)",
+			values:   []interface{}{"john@mail.com", 1, "john"},
+		},
+		{
+			name:    "mysql set expression with value",
+			dialect: MySQL,
+			build: func(query InsertBuilderInteractor) {
+				query.AddOnConflictUpdate([]string{"id"})
+				query.AddOnConflictSetExpression("visit", Raw("`visit` + ?", 1))
+			},
+			expected: "INSERT INTO `users` (`email`, `id`, `name`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `visit` = `visit` + ?",
+			values:   []interface{}{"john@mail.com", 1, "john", 1},
+		},
+		{
+			name:    "mysql do nothing",
+			dialect: MySQL,
+			build: func(query InsertBuilderInteractor) {
+				query.AddOnConflictDoNothing("id")
+			},
+			expected: "INSERT INTO `users` (`email`, `id`, `name`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `email` = `email`",
+			values:   []interface{}{"john@mail.com", 1, "john"},
+		},
+	}
+
+	for _, test := range tests {
+		query := NewInsertBuilder(WithDialect(test.dialect))
+		query.AddRow(row)
+		test.build(query)
+
+		res, values, err := query.GetQuery("users")
+		assertQuery(t, test.name, res, values, err, test.expected, test.values)
+	}
+}
+
+func TestInsertOnConflictWithoutConflictColumns(t *testing.T) {
+	for _, build := range []func(query InsertBuilderInteractor){
+		func(query InsertBuilderInteractor) { query.AddOnConflictUpdate(nil) },
+		func(query InsertBuilderInteractor) { query.AddOnConflictSetExpression("visit", Raw("1")) },
+	} {
+		query := NewInsertBuilder(WithDialect(PostgreSQL))
+		query.AddRow(map[string]interface{}{"id": 1, "name": "john"})
+		build(query)
+
+		if res, values, err := query.GetQuery("users"); err == nil || res != "" || values != nil {
+			t.Errorf("expected error and empty query, got %q %v %v", res, values, err)
+		}
+	}
+}
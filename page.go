@@ -0,0 +1,126 @@
+package goutils
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// =================================================
+
+// Page page of items with meta pagination response
+type Page[T any] struct {
+	Items []T          `json:"items"`
+	Meta  MetaResponse `json:"meta"`
+}
+
+// PageOption option of FetchPage
+type PageOption func(option *pageOption)
+
+type pageOption struct {
+	concurrent bool
+	skipCount  bool
+}
+
+// WithConcurrentCount run count and page query concurrently, executor must be safe for concurrent use, e.g. *sql.DB
+func WithConcurrentCount() PageOption {
+	return func(option *pageOption) {
+		option.concurrent = true
+	}
+}
+
+// WithoutCount skip count query, total and total pages of meta response are zero
+func WithoutCount() PageOption {
+	return func(option *pageOption) {
+		option.skipCount = true
+	}
+}
+
+// CursorResult page of items with meta cursor pagination response
+type CursorResult[T any] struct {
+	Items []T                `json:"items"`
+	Meta  MetaCursorResponse `json:"meta"`
+}
+
+// FetchPage set pagination and sort of request into copy of query, then fetch items of page and total rows of query.
+// First page of 10 rows is fetched when request has no pagination, use FetchCursorPage for cursor pagination
+func FetchPage[T any](ctx context.Context, executor ExecutorInteractor, request *RequestOption, query QueryBuilderInteractor, tablename string, aliases string, opts ...PageOption) (result Page[T], err error) {
+	if request.GetCursorPagination() != nil {
+		err = errors.New("cursor pagination is not supported by FetchPage, use FetchCursorPage")
+		return
+	}
+
+	option := pageOption{}
+	for _, opt := range opts {
+		opt(&option)
+	}
+
+	query, page, limit := request.SetPaginationWithSort(query.Clone())
+	if limit <= 0 {
+		pagination := NewPagination(1, 0)
+		query.AddPagination(pagination)
+		page, limit = pagination.GetPage(), pagination.GetLimit()
+	}
+
+	var (
+		total    int
+		countErr error
+		wg       sync.WaitGroup
+	)
+
+	count := func() {
+		if !option.skipCount {
+			total, countErr = executor.Count(ctx, query, tablename, aliases)
+		}
+	}
+
+	if option.concurrent {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			count()
+		}()
+	} else {
+		count()
+		if countErr != nil {
+			return result, countErr
+		}
+	}
+
+	items := make([]T, 0)
+	err = executor.Select(ctx, &items, query, tablename, aliases)
+	wg.Wait()
+	if err != nil {
+		return
+	}
+
+	if countErr != nil {
+		return result, countErr
+	}
+
+	result.Items = items
+	result.Meta = MapMetaResponse(total, len(items), page, limit)
+	return
+}
+
+// FetchCursorPage set cursor pagination and sort of request into copy of query, then fetch items of page
+// with next and prev cursors, values return sort column values of item, see CursorPage
+func FetchCursorPage[T any](ctx context.Context, executor ExecutorInteractor, request *RequestOption, query QueryBuilderInteractor, tablename string, aliases string, values func(item T) []interface{}) (result CursorResult[T], err error) {
+	cursor := request.GetCursorPagination()
+	if cursor == nil {
+		err = errors.New("cursor pagination is required by FetchCursorPage")
+		return
+	}
+
+	query, _, _ = request.SetPaginationWithSort(query.Clone())
+
+	rows := make([]T, 0)
+	if err = executor.Select(ctx, &rows, query, tablename, aliases); err != nil {
+		return
+	}
+
+	items, next, prev := CursorPage(cursor, rows, values)
+	result.Items = items
+	result.Meta = MapMetaCursorResponse(len(items), cursor.GetLimit(), next, prev)
+	return
+}
@@ -0,0 +1,70 @@
+package goutils
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestFetchPageKeepsBaseQuery(t *testing.T) {
+	executor := newStubExecutor(t, map[string]*stubRows{
+		"SELECT COUNT(*)": {columns: []string{"count"}, data: [][]driver.Value{{int64(3)}}},
+		"SELECT":          stubUserRows,
+	})
+
+	request := NewRequestOption().SetPagination(NewPagination(1, 2))
+	if _, err := request.SetSortBy(DirDesc, "id"); err != nil {
+		t.Fatal(err)
+	}
+
+	base := NewQueryBuilder(WithDialect(PostgreSQL))
+	base.AddWhere("id", "gt", 0)
+	for i := 0; i < 2; i++ {
+		page, err := FetchPage[stubUser](context.Background(), executor, request, base, "users", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(page.Items) != 2 || page.Meta.Data.Total != 3 || page.Meta.Data.TotalPages != 2 {
+			t.Errorf("unexpected page %+v", page)
+		}
+	}
+
+	for _, query := range stub.queries {
+		if query != `SELECT COUNT(*) FROM "users" WHERE "id" > $1` && query != `SELECT * FROM "users" WHERE "id" > $1 ORDER BY "id" DESC LIMIT 2 OFFSET 0` {
+			t.Errorf("unexpected query %q", query)
+		}
+	}
+
+	res, values, err := base.GetQuery("users", "")
+	assertQuery(t, "base", res, values, err, `SELECT * FROM "users" WHERE "id" > $1`, []interface{}{0})
+}
+
+func TestFetchCursorPage(t *testing.T) {
+	executor := newStubExecutor(t, map[string]*stubRows{"SELECT": stubUserRows})
+
+	cursor, _ := NewCursorPagination("", 1)
+	request := NewRequestOption().SetCursorPagination(cursor)
+	if _, err := request.SetSortBy(DirAsc, "id"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := FetchPage[stubUser](context.Background(), executor, request, NewQueryBuilder(), "users", ""); err == nil {
+		t.Error("expected error of cursor request in FetchPage")
+	}
+
+	page, err := FetchCursorPage(context.Background(), executor, request, NewQueryBuilder(WithDialect(PostgreSQL)), "users", "", func(user stubUser) []interface{} {
+		return []interface{}{user.ID}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(page.Items) != 1 || page.Meta.Data.NextCursor != NextCursor(int64(1)) || page.Meta.Data.PrevCursor != "" {
+		t.Errorf("unexpected page %+v", page)
+	}
+
+	if query := stub.queries[len(stub.queries)-1]; query != `SELECT * FROM "users" ORDER BY "id" ASC LIMIT 2` {
+		t.Errorf("unexpected query %q", query)
+	}
+}
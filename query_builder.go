@@ -3,8 +3,11 @@ package goutils
 import (
 	"errors"
 	"fmt"
-
-	"github.com/Muruyung/go-utilities/logger"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 // =================================================
@@ -37,28 +40,58 @@ var (
 // QueryBuilderInteractor query builder interactor
 type QueryBuilderInteractor interface {
 	GetQuery(tablename string, aliases string) (query string, values []interface{}, err error)
+	GetCountQuery(tablename string, aliases string) (query string, values []interface{}, err error)
 	AddSelection(selection string)
+	AddRawSelection(expression rawExpression)
+	AddSubquerySelection(subquery subquery, aliases string)
+	AddFrom(subquery subquery)
+	AddWith(name string, subquery subquery)
+	AddSetOperation(operation setOperation, subquery subquery)
+	AddWithRecursive(name string, columns []string, anchor subquery, recursive subquery)
 	AddSum(column string, aliases string)
 	AddCount(column string, aliases string)
+	AddCountAll(aliases string)
+	AddCountDistinct(column string, aliases string)
+	AddAvg(column string, aliases string)
+	AddMin(column string, aliases string)
+	AddMax(column string, aliases string)
+	AddStringAgg(column string, separator string, aliases string)
+	AddWindowFunction(function windowFunction, window *windowOption, aliases string)
 	AddPagination(pagination *paginationOption)
+	AddCursorPagination(cursor *cursorOption)
 	AddSort(direction direction, sortBy ...string)
 	AddWhere(attribute string, operation string, value interface{})
 	AddRawWhere(listWhere map[string]interface{})
+	AddWhereExpression(expression rawExpression)
+	AddCondition(condition ...Condition)
 	AddJoin(joinType joinType, tableName, aliases, on string)
+	AddRawJoin(joinType joinType, tableName, aliases string, on rawExpression)
 	AddGroup(group ...string)
+	AddHaving(attribute string, operation string, value interface{})
+	AddRawHaving(listHaving map[string]interface{})
 	AddKey(key ...interface{})
 	RemoveKey()
 	GetKey() string
+	GetCacheKey(tablename string, aliases string) (string, error)
+	Clone() QueryBuilderInteractor
 }
 
 type queryBuilder struct {
-	selection  *[]string
-	sort       *[]string
-	where      *map[string]interface{}
+	selection  *[]expression
+	from       *subquery
+	with       *[]commonTable
+	compound   *[]compound
+	sort       *[]sortItem
+	where      *[]map[string]interface{}
 	pagination *paginationOption
+	cursor     *cursorOption
 	join       *[]join
 	group      *[]string
+	having     *[]map[string]interface{}
 	key        string
+	option     builderOption
+	errs       []error
+	mu         sync.RWMutex
 }
 
 // JoinType type of join table
@@ -88,20 +121,29 @@ type join struct {
 	tableName string
 	aliases   string
 	on        string
+	rawOn     *rawExpression
 }
 
 // NewQueryBuilder create new query builder
-func NewQueryBuilder() QueryBuilderInteractor {
+func NewQueryBuilder(opts ...QueryBuilderOption) QueryBuilderInteractor {
 	return &queryBuilder{
 		selection:  nil,
 		sort:       nil,
 		where:      nil,
 		pagination: nil,
+		option:     newBuilderOption(opts...),
 	}
 }
 
 // AddKey add cache key
 func (q *queryBuilder) AddKey(key ...interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addKey(key...)
+}
+
+func (q *queryBuilder) addKey(key ...interface{}) {
 	for _, val := range key {
 		if q.key != "" {
 			q.key = fmt.Sprintf("%s-%v", q.key, val)
@@ -113,79 +155,273 @@ func (q *queryBuilder) AddKey(key ...interface{}) {
 
 // RemoveKey remove cache key
 func (q *queryBuilder) RemoveKey() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
 	q.key = ""
 }
 
 // GetKey get cache key
 func (q *queryBuilder) GetKey() string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
 	return q.key
 }
 
 // GetQuery parse query
 func (q *queryBuilder) GetQuery(tablename string, aliases string) (query string, values []interface{}, err error) {
-	logger.InitLogger("local", "")
-	query = `SELECT`
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	query, values, err = q.parseQuery(q.option.dialect, tablename, aliases)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query = q.option.dialect.rebind(query)
+	return
+}
+
+// GetCountQuery parse count query of total rows, from, join and where are same with GetQuery,
+// sort and pagination are removed and query is wrapped in subquery when it has group, having, distinct, aggregate or set operation
+func (q *queryBuilder) GetCountQuery(tablename string, aliases string) (query string, values []interface{}, err error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	query, values, err = q.parseCountQuery(q.option.dialect, tablename, aliases)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query = q.option.dialect.rebind(query)
+	return
+}
+
+func (q *queryBuilder) parseCountQuery(d dialect, tablename string, aliases string) (query string, values []interface{}, err error) {
+	count := q.clone()
+	count.sort, count.pagination, count.cursor = nil, nil, nil
+	if count.group == nil && count.having == nil && count.compound == nil && !count.hasDistinct() && !count.hasAggregate() {
+		count.selection = &[]expression{identifierExpression("COUNT(*)")}
+		return count.parseQuery(d, tablename, aliases)
+	}
+
+	with := count.with
+	count.with = nil
+	query, values, err = count.parseQuery(d, tablename, aliases)
+	if err != nil {
+		return
+	}
+	query = fmt.Sprintf(`SELECT COUNT(*) FROM (%s) %s`, query, d.quoteIdentifier("count_query"))
+
+	if with != nil {
+		var (
+			prefix string
+			v      []interface{}
+		)
+		prefix, v, err = parseWith(d, *with)
+		if err != nil {
+			return
+		}
+		query = fmt.Sprintf(`%s %s`, prefix, query)
+		values = append(v, values...)
+	}
+
+	return
+}
+
+// hasDistinct selection has distinct, e.g. AddRawSelection(Raw("DISTINCT name"))
+func (q *queryBuilder) hasDistinct() bool {
 	if q.selection == nil {
-		query = fmt.Sprintf(`%s *`, query)
-	} else {
-		for key, val := range *q.selection {
-			if key > 0 {
-				query = fmt.Sprintf(`%s,`, query)
-			}
-			query = fmt.Sprintf(`%s %s`, query, val)
+		return false
+	}
+
+	for _, val := range *q.selection {
+		if expr, ok := val.(rawExpression); ok && distinctPattern.MatchString(expr.query) {
+			return true
 		}
 	}
+	return false
+}
 
-	if aliases != "" {
-		aliases = fmt.Sprintf(` %s`, aliases)
+// hasAggregate selection has aggregate, e.g. AddSum, AddSelection("COUNT(*) total") or AddStringAgg,
+// such query returns single row without group
+func (q *queryBuilder) hasAggregate() bool {
+	if q.selection == nil {
+		return false
 	}
 
-	query = fmt.Sprintf(`%s FROM %s%s`, query, tablename, aliases)
+	for _, val := range *q.selection {
+		if aliased, ok := val.(aliasedExpression); ok {
+			val = aliased.expression
+		}
+
+		switch expr := val.(type) {
+		case stringAgg:
+			return true
+		case identifierExpression:
+			if aggregateCallPattern.MatchString(string(expr)) {
+				return true
+			}
+		case rawExpression:
+			if aggregateCallPattern.MatchString(expr.query) {
+				return true
+			}
+		}
+	}
+	return false
+}
 
+// parseQuery parse query with ? placeholder, so it can be nested into another query before rebind
+func (q *queryBuilder) parseQuery(d dialect, tablename string, aliases string) (query string, values []interface{}, err error) {
 	var (
+		selection  string
+		from       string
 		join       string
 		where      string
 		sort       string
 		pagination string
 		group      string
+		v          []interface{}
 	)
+
+	if len(q.errs) > 0 {
+		err = append(QueryErrors{}, q.errs...)
+		return
+	}
+
+	query = `SELECT`
+	if q.selection == nil {
+		query = fmt.Sprintf(`%s *`, query)
+	} else {
+		selection, values, err = parseSelection(d, *q.selection)
+		if err != nil {
+			return
+		}
+		query = fmt.Sprintf(`%s %s`, query, selection)
+	}
+
+	if aliases != "" {
+		if aliases, err = d.identifier(aliases); err != nil {
+			return
+		}
+		aliases = fmt.Sprintf(` %s`, aliases)
+	}
+
+	if q.from != nil {
+		from, v, err = q.from.parse(d)
+		values = append(values, v...)
+	} else {
+		from, err = d.identifier(tablename)
+	}
+	if err != nil {
+		return
+	}
+
+	query = fmt.Sprintf(`%s FROM %s%s`, query, from, aliases)
+
 	if q.join != nil {
-		join = parseJoin(*q.join...)
+		join, v, err = parseJoin(d, *q.join...)
+		if err != nil {
+			return
+		}
 		query = fmt.Sprintf(`%s %s`, query, join)
+		values = append(values, v...)
+	}
+
+	var sorts []sortItem
+	if q.sort != nil {
+		sorts = *q.sort
 	}
 
 	if q.where != nil {
-		where, values, err = parseWhere(*q.where)
+		where, v, err = parseWhereList(d, "where", *q.where)
+		if err != nil {
+			return
+		}
+		values = append(values, v...)
+	}
+
+	if q.cursor != nil {
+		// cursor predicate would only filter the first branch of set operation
+		if q.compound != nil {
+			err = newQueryError(ErrInvalidValue, "cursor", (*q.compound)[0].operation, nil)
+			return
+		}
+
+		var cursor string
+		sorts = cursorSort(*q.cursor, sorts)
+		cursor, v, err = parseCursor(d, *q.cursor, sorts)
 		if err != nil {
-			logger.Logger.Error(err)
 			return
 		}
+
+		if where == "" {
+			where = cursor
+		} else if cursor != "" {
+			where = fmt.Sprintf("(%s AND %s)", where, cursor)
+		}
+		values = append(values, v...)
+	}
+
+	if where != "" {
 		query = fmt.Sprintf(`%s WHERE %s`, query, where)
 	}
 
 	if q.group != nil {
-		group, err = parseGroup(*q.group)
+		group, err = parseGroup(d, *q.group)
 		if err != nil {
-			logger.Logger.Error(err)
 			return
 		}
 		query = fmt.Sprintf(`%s GROUP BY %s`, query, group)
 	}
 
-	if q.sort != nil {
-		sort, err = parseSort(*q.sort)
+	if q.having != nil {
+		var having string
+		having, v, err = parseWhereList(d, "having", *q.having)
+		if err != nil {
+			return
+		}
+		query = fmt.Sprintf(`%s HAVING %s`, query, having)
+		values = append(values, v...)
+	}
+
+	if q.compound != nil {
+		var compound string
+		compound, v, err = parseCompound(d, *q.compound)
+		if err != nil {
+			return
+		}
+		query = fmt.Sprintf(`%s %s`, query, compound)
+		values = append(values, v...)
+	}
+
+	if len(sorts) > 0 {
+		sort, err = parseSort(d, sorts)
 		if err != nil {
-			logger.Logger.Error(err)
 			return
 		}
 		query = fmt.Sprintf(`%s ORDER BY %s`, query, sort)
 	}
 
-	if q.pagination != nil {
+	if q.cursor != nil {
+		// one extra row is fetched so CursorPage knows whether there is another page
+		query = fmt.Sprintf(`%s LIMIT %d`, query, q.cursor.limit+1)
+	} else if q.pagination != nil {
 		pagination = parsePagination(*q.pagination)
 		query = fmt.Sprintf(`%s %s`, query, pagination)
 	}
 
+	if q.with != nil {
+		var with string
+		with, v, err = parseWith(d, *q.with)
+		if err != nil {
+			return
+		}
+		query = fmt.Sprintf(`%s %s`, with, query)
+		values = append(v, values...)
+	}
+
 	return
 }
 
@@ -193,7 +429,33 @@ func (q *queryBuilder) GetQuery(tablename string, aliases string) (query string,
 
 // AddSelection add selection query
 func (q *queryBuilder) AddSelection(selection string) {
-	arrSelection := make([]string, 0)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addSelection(identifierExpression(selection))
+}
+
+// AddRawSelection add selection query with raw sql expression
+func (q *queryBuilder) AddRawSelection(expression rawExpression) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addSelection(expression)
+}
+
+// AddSubquerySelection add subquery as selected column
+func (q *queryBuilder) AddSubquerySelection(subquery subquery, aliases string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addSelection(aliasedExpression{
+		expression: subquery,
+		aliases:    aliases,
+	})
+}
+
+func (q *queryBuilder) addSelection(selection expression) {
+	arrSelection := make([]expression, 0)
 	if q.selection != nil {
 		arrSelection = append(arrSelection, *q.selection...)
 	}
@@ -202,25 +464,134 @@ func (q *queryBuilder) AddSelection(selection string) {
 	q.selection = &arrSelection
 }
 
+func parseSelection(d dialect, selections []expression) (query string, values []interface{}, err error) {
+	for i, val := range selections {
+		q, v, err := val.parse(d)
+		if err != nil {
+			return query, values, prependPath(err, "selection", strconv.Itoa(i))
+		}
+
+		if query != "" {
+			query = fmt.Sprintf("%s, %s", query, q)
+		} else {
+			query = q
+		}
+		values = append(values, v...)
+	}
+	return
+}
+
 // AddSum add sum query
 func (q *queryBuilder) AddSum(column string, aliases string) {
-	selection := fmt.Sprintf(`SUM(%s) %s`, column, aliases)
-	q.AddSelection(selection)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addAggregate("SUM", column, aliases)
 }
 
-// AddCount add count query
+// AddCount add count distinct query, kept for backward compatibility, use AddCountDistinct or AddCountAll instead
 func (q *queryBuilder) AddCount(column string, aliases string) {
-	selection := fmt.Sprintf(`COUNT(DISTINCT %s) %s`, column, aliases)
-	q.AddSelection(selection)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addAggregate("COUNT", "DISTINCT "+column, aliases)
+}
+
+// AddCountAll add count(*) query
+func (q *queryBuilder) AddCountAll(aliases string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addAggregate("COUNT", "*", aliases)
+}
+
+// AddCountDistinct add count distinct query
+func (q *queryBuilder) AddCountDistinct(column string, aliases string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addAggregate("COUNT", "DISTINCT "+column, aliases)
+}
+
+// AddAvg add average query
+func (q *queryBuilder) AddAvg(column string, aliases string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addAggregate("AVG", column, aliases)
+}
+
+// AddMin add minimum query
+func (q *queryBuilder) AddMin(column string, aliases string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addAggregate("MIN", column, aliases)
+}
+
+// AddMax add maximum query
+func (q *queryBuilder) AddMax(column string, aliases string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addAggregate("MAX", column, aliases)
+}
+
+// AddStringAgg add string aggregation query, STRING_AGG for postgres and GROUP_CONCAT for mysql and sqlite
+func (q *queryBuilder) AddStringAgg(column string, separator string, aliases string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addSelection(aliasedExpression{
+		expression: stringAgg{
+			column:    column,
+			separator: separator,
+		},
+		aliases: aliases,
+	})
+}
+
+func (q *queryBuilder) addAggregate(function string, column string, aliases string) {
+	q.addSelection(aliasedExpression{
+		expression: identifierExpression(fmt.Sprintf(`%s(%s)`, function, column)),
+		aliases:    aliases,
+	})
+}
+
+// stringAgg string aggregation expression
+type stringAgg struct {
+	column    string
+	separator string
+}
+
+func (s stringAgg) parse(d dialect) (query string, values []interface{}, err error) {
+	column, err := d.column(s.column, false)
+	if err != nil {
+		return
+	}
+
+	separator := d.quoteString(s.separator)
+	switch d {
+	case MySQL:
+		query = fmt.Sprintf(`GROUP_CONCAT(%s SEPARATOR %s)`, column, separator)
+	case SQLite:
+		query = fmt.Sprintf(`GROUP_CONCAT(%s, %s)`, column, separator)
+	default:
+		query = fmt.Sprintf(`STRING_AGG(%s, %s)`, column, separator)
+	}
+	return
 }
 
 // =================================================
 
 // AddPagination add pagination query
 func (q *queryBuilder) AddPagination(pagination *paginationOption) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
 	q.pagination = pagination
-	q.AddKey("limit", pagination.limit)
-	q.AddKey("offset", pagination.offset)
+	q.addKey("limit", pagination.limit)
+	q.addKey("offset", pagination.offset)
 }
 
 func parsePagination(pagination paginationOption) (query string) {
@@ -232,6 +603,9 @@ func parsePagination(pagination paginationOption) (query string) {
 
 // AddGroup add group query
 func (q *queryBuilder) AddGroup(group ...string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
 	arrGroup := make([]string, 0)
 	if q.group != nil {
 		arrGroup = append(arrGroup, *q.group...)
@@ -241,8 +615,39 @@ func (q *queryBuilder) AddGroup(group ...string) {
 	q.group = &arrGroup
 }
 
-func parseGroup(groups []string) (query string, err error) {
-	for _, val := range groups {
+// AddHaving add having query, using the same condition grammar as where
+func (q *queryBuilder) AddHaving(attribute string, operation string, value interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if operation == "" || operation == "eq" || operation == "=" {
+		q.addKey("having", attribute, value)
+	} else {
+		q.addKey("having", attribute, operation, value)
+	}
+
+	q.having = appendWhere(q.having, buildWhere(attribute, operation, value))
+}
+
+// AddRawHaving add raw having query
+func (q *queryBuilder) AddRawHaving(listHaving map[string]interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	having := make(map[string]interface{})
+	for key, val := range listHaving {
+		having[key] = val
+	}
+
+	q.having = appendWhere(q.having, having)
+}
+
+func parseGroup(d dialect, groups []string) (query string, err error) {
+	for i, val := range groups {
+		if val, err = d.column(val, false); err != nil {
+			return query, prependPath(err, "group", strconv.Itoa(i))
+		}
+
 		if query != "" {
 			query = fmt.Sprintf("%s, %s", query, val)
 		} else {
@@ -254,26 +659,48 @@ func parseGroup(groups []string) (query string, err error) {
 
 // =================================================
 
+type sortItem struct {
+	column    string
+	direction direction
+}
+
 // AddSort add sort query
 func (q *queryBuilder) AddSort(direction direction, sortBy ...string) {
-	sort := make([]string, 0)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	sort := make([]sortItem, 0)
 	if q.sort != nil {
-		sort = *q.sort
+		sort = append(sort, *q.sort...)
 	}
 
 	for _, val := range sortBy {
-		sort = append(sort, fmt.Sprintf("%s %s", val, direction.dir))
-		q.AddKey(val, direction.dir)
+		sort = append(sort, sortItem{
+			column:    val,
+			direction: direction,
+		})
+		q.addKey(val, direction.dir)
 	}
 	q.sort = &sort
 }
 
-func parseSort(sorts []string) (query string, err error) {
-	for _, val := range sorts {
+func parseSort(d dialect, sorts []sortItem) (query string, err error) {
+	for i, val := range sorts {
+		if val.direction.dir == "" {
+			err = prependPath(newQueryError(ErrInvalidValue, val.column, "", nil), "sort", strconv.Itoa(i))
+			return
+		}
+
+		var column string
+		if column, err = d.column(val.column, false); err != nil {
+			return query, prependPath(err, "sort", strconv.Itoa(i))
+		}
+
+		sort := fmt.Sprintf("%s %s", column, val.direction.dir)
 		if query != "" {
-			query = fmt.Sprintf("%s, %s", query, val)
+			query = fmt.Sprintf("%s, %s", query, sort)
 		} else {
-			query = val
+			query = sort
 		}
 	}
 	return
@@ -283,6 +710,9 @@ func parseSort(sorts []string) (query string, err error) {
 
 // AddJoin add join query
 func (q *queryBuilder) AddJoin(joinType joinType, tableName string, aliases string, on string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
 	tmpJoin := make([]join, 0)
 	if q.join != nil {
 		tmpJoin = append(tmpJoin, *q.join...)
@@ -297,65 +727,183 @@ func (q *queryBuilder) AddJoin(joinType joinType, tableName string, aliases stri
 	q.join = &tmpJoin
 }
 
-func parseJoin(arrJoin ...join) (query string) {
-	for _, join := range arrJoin {
+// AddRawJoin add join query with raw sql condition
+func (q *queryBuilder) AddRawJoin(joinType joinType, tableName string, aliases string, on rawExpression) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	tmpJoin := make([]join, 0)
+	if q.join != nil {
+		tmpJoin = append(tmpJoin, *q.join...)
+	}
+
+	tmpJoin = append(tmpJoin, join{
+		joinType:  string(joinType.join),
+		tableName: tableName,
+		aliases:   aliases,
+		rawOn:     &on,
+	})
+	q.join = &tmpJoin
+}
+
+func parseJoin(d dialect, arrJoin ...join) (query string, values []interface{}, err error) {
+	arrQuery := make([]string, 0, len(arrJoin))
+	for i, join := range arrJoin {
+		var (
+			tableName string
+			on        string
+			v         []interface{}
+			path      = []string{"join", strconv.Itoa(i)}
+		)
+		if join.joinType == "" {
+			err = prependPath(newQueryError(ErrInvalidOperator, join.tableName, "", nil), path...)
+			return
+		}
+
+		if tableName, err = d.identifier(join.tableName); err != nil {
+			return query, values, prependPath(err, path...)
+		}
+
 		if join.aliases != "" {
+			if join.aliases, err = d.identifier(join.aliases); err != nil {
+				return query, values, prependPath(err, path...)
+			}
 			join.aliases = fmt.Sprintf(" %s", join.aliases)
 		}
-		query = fmt.Sprintf("%s %s JOIN %s%s ON %s",
-			query, join.joinType, join.tableName, join.aliases, join.on,
-		)
+
+		if join.rawOn != nil {
+			on, v, err = join.rawOn.parse(d)
+		} else {
+			on, err = d.parseOn(join.on)
+		}
+		if err != nil {
+			return query, values, prependPath(err, path...)
+		}
+
+		arrQuery = append(arrQuery, fmt.Sprintf("%s JOIN %s%s ON %s",
+			join.joinType, tableName, join.aliases, on,
+		))
+		values = append(values, v...)
 	}
 
+	query = strings.Join(arrQuery, " ")
 	return
 }
 
 // =================================================
 
-// AddWhere add where query
+// AddWhere add where query, conditions are rendered in insertion order
 func (q *queryBuilder) AddWhere(attribute string, operation string, value interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if operation == "" || operation == "eq" || operation == "=" {
+		q.addKey(attribute, value)
+	} else {
+		q.addKey(attribute, operation, value)
+	}
+
+	q.where = appendWhere(q.where, buildWhere(attribute, operation, value))
+}
+
+// AddWhereExpression add where query with raw sql expression
+func (q *queryBuilder) AddWhereExpression(expression rawExpression) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addKey(expression)
+	q.where = appendWhere(q.where, map[string]interface{}{
+		RawKey: expression,
+	})
+}
+
+// AddRawWhere add raw where query, keys of listWhere are rendered in sorted order
+func (q *queryBuilder) AddRawWhere(listWhere map[string]interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
 	where := make(map[string]interface{})
-	if q.where != nil {
-		where = *q.where
+	for key, val := range listWhere {
+		where[key] = val
 	}
 
+	q.where = appendWhere(q.where, where)
+}
+
+func buildWhere(attribute string, operation string, value interface{}) map[string]interface{} {
 	if operation == "" || operation == "eq" || operation == "=" {
-		where[attribute] = value
-		q.AddKey(attribute, value)
-	} else {
-		where[operation] = map[string]interface{}{
+		return map[string]interface{}{
 			attribute: value,
 		}
-		q.AddKey(attribute, operation, value)
 	}
 
-	q.where = &where
+	return map[string]interface{}{
+		operation: map[string]interface{}{
+			attribute: value,
+		},
+	}
 }
 
-// AddRawWhere add raw where query
-func (q *queryBuilder) AddRawWhere(listWhere map[string]interface{}) {
-	where := make(map[string]interface{})
-	if q.where != nil {
-		where = *q.where
+func appendWhere(listWhere *[]map[string]interface{}, where map[string]interface{}) *[]map[string]interface{} {
+	arrWhere := make([]map[string]interface{}, 0)
+	if listWhere != nil {
+		arrWhere = append(arrWhere, *listWhere...)
 	}
 
-	for key, val := range listWhere {
-		where[key] = val
+	arrWhere = append(arrWhere, where)
+	return &arrWhere
+}
+
+// sortedKeys keys of map in sorted order, so the parsed query is deterministic
+func sortedKeys(data map[string]interface{}) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// parseWhereList parse list of where joined with AND, section is the root of path in query error
+func parseWhereList(d dialect, section string, listWhere []map[string]interface{}) (query string, values []interface{}, err error) {
+	for i, where := range listWhere {
+		q, v, err := parseWhere(d, where)
+		if err != nil {
+			return query, values, prependPath(err, section, strconv.Itoa(i))
+		}
+
+		if q == "" {
+			continue
+		}
+
+		if query == "" {
+			query = q
+		} else {
+			query = fmt.Sprintf("(%s AND %s)", query, q)
+		}
+
+		values = append(values, v...)
 	}
-	q.where = &where
+
+	return
 }
 
-func parseWhere(where map[string]interface{}) (query string, values []interface{}, err error) {
+// RawKey key of raw sql expression or typed condition in where map,
+// e.g. map[string]interface{}{RawKey: Raw("a = b")} or map[string]interface{}{RawKey: Eq("a", 1)}
+const RawKey = "RAW"
+
+func parseWhere(d dialect, where map[string]interface{}) (query string, values []interface{}, err error) {
 	query = ""
-	for key, val := range where {
+	for _, key := range sortedKeys(where) {
+		val := where[key]
 		switch key {
 		case "AND", "OR", "NOT":
 			switch value := val.(type) {
 			case map[string]interface{}:
-				q, v, err := parseBoolOperator(key, value)
+				q, v, err := parseBoolOperator(d, key, value)
 				if err != nil {
-					logger.Logger.Error(err)
-					return query, values, err
+					return query, values, prependPath(err, key)
 				}
 
 				if query == "" {
@@ -366,33 +914,62 @@ func parseWhere(where map[string]interface{}) (query string, values []interface{
 
 				values = append(values, v...)
 			case []map[string]interface{}:
-				for _, arrVal := range value {
-					q, v, err := parseBoolOperator(key, arrVal)
+				// every NOT group is negated on its own, so the groups are joined with AND
+				joiner := key
+				if key == "NOT" {
+					joiner = "AND"
+				}
+
+				for i, arrVal := range value {
+					q, v, err := parseBoolOperator(d, key, arrVal)
 					if err != nil {
-						return query, values, err
+						return query, values, prependPath(err, key, strconv.Itoa(i))
 					}
 
 					if query == "" {
 						query = q
 					} else {
-						query = fmt.Sprintf("(%s %s %s)", query, key, q)
+						query = fmt.Sprintf("(%s %s %s)", query, joiner, q)
 					}
 
 					values = append(values, v...)
 				}
 			default:
-				err = fmt.Errorf("invalid value for %v", value)
-				logger.Logger.Error(err)
+				err = prependPath(newQueryError(ErrInvalidValue, "", key, value), key)
+				return
+			}
+		case RawKey:
+			expr, ok := val.(expression)
+			if !ok {
+				err = prependPath(newQueryError(ErrInvalidValue, "", key, val), key)
 				return
 			}
+
+			q, v, err := expr.parse(d)
+			if err != nil {
+				return query, values, prependPath(err, key)
+			}
+			if query == "" {
+				query = q
+			} else {
+				query = fmt.Sprintf("(%s AND %s)", query, q)
+			}
+
+			values = append(values, v...)
 		case "BETWEEN":
 			switch value := val.(type) {
 			case map[string]interface{}:
-				for k, v := range value {
-					switch dateVal := v.(type) {
+				for _, k := range sortedKeys(value) {
+					switch dateVal := value[k].(type) {
 					case map[string]interface{}:
-						for k2, v2 := range dateVal {
-							q := fmt.Sprintf("(%s BETWEEN ? AND ?)", k)
+						column, err := d.column(k, false)
+						if err != nil {
+							return query, values, prependPath(err, key, k)
+						}
+
+						for _, k2 := range sortedKeys(dateVal) {
+							v2 := dateVal[k2]
+							q := fmt.Sprintf("(%s BETWEEN ? AND ?)", column)
 
 							if query == "" {
 								query = q
@@ -403,21 +980,18 @@ func parseWhere(where map[string]interface{}) (query string, values []interface{
 							values = append(values, k2, v2)
 						}
 					default:
-						err = fmt.Errorf("invalid value for %v", v)
-						logger.Logger.Error(err)
+						err = prependPath(newQueryError(ErrInvalidValue, k, key, value[k]), key, k)
 						return
 					}
 				}
 			default:
-				err = fmt.Errorf("invalid value for %v", value)
-				logger.Logger.Error(err)
+				err = prependPath(newQueryError(ErrInvalidValue, "", key, value), key)
 				return
 			}
 		default:
-			q, value, err := parseValueOperator(key, val)
+			q, value, err := parseValueOperator(d, key, val)
 			if err != nil {
-				logger.Logger.Error(err)
-				return query, values, err
+				return query, values, prependPath(err, key)
 			}
 
 			if query == "" {
@@ -434,8 +1008,33 @@ func parseWhere(where map[string]interface{}) (query string, values []interface{
 	return
 }
 
-func getOperation(key string, op string, value interface{}) (res string) {
+func getOperation(d dialect, key string, op string, value interface{}) (res string, values []interface{}, err error) {
+	if expr, ok := value.(expression); ok && (op == "exists" || op == "not_exists") {
+		return getExpressionOperation(d, key, op, expr)
+	}
+
+	attribute := key
+	if key, err = d.column(key, false); err != nil {
+		var queryErr *QueryError
+		if errors.As(err, &queryErr) {
+			queryErr.Operator = op
+		}
+		return
+	}
+
+	if expr, ok := value.(expression); ok {
+		return getExpressionOperation(d, key, op, expr)
+	}
+
+	values = []interface{}{value}
+
 	switch op {
+	case "", "eq", "=":
+		if value == nil {
+			res, values = fmt.Sprintf("%s IS NULL", key), nil
+		} else {
+			res = fmt.Sprintf("%s = ?", key)
+		}
 	case "lte", "<=":
 		res = fmt.Sprintf("%s <= ?", key)
 	case "lt", "<":
@@ -444,85 +1043,144 @@ func getOperation(key string, op string, value interface{}) (res string) {
 		res = fmt.Sprintf("%s >= ?", key)
 	case "gt", ">":
 		res = fmt.Sprintf("%s > ?", key)
-	case "eq", "=":
-		if value == nil {
-			res = fmt.Sprintf("%s IS NULL", key)
-		} else {
-			res = fmt.Sprintf("%s = ?", key)
-		}
 	case "is":
 		if value == nil {
-			res = fmt.Sprintf("%s IS NULL", key)
+			res, values = fmt.Sprintf("%s IS NULL", key), nil
 		} else {
 			res = fmt.Sprintf("%s IS ?", key)
 		}
 	case "neq", "!=":
 		if value == nil {
-			res = fmt.Sprintf("%s IS NOT NULL", key)
+			res, values = fmt.Sprintf("%s IS NOT NULL", key), nil
 		} else {
 			res = fmt.Sprintf("%s != ?", key)
 		}
 	case "is_not":
 		if value == nil {
-			res = fmt.Sprintf("%s IS NOT NULL", key)
+			res, values = fmt.Sprintf("%s IS NOT NULL", key), nil
 		} else {
 			res = fmt.Sprintf("%s IS NOT ?", key)
 		}
-	case "in":
-		res = fmt.Sprintf("%s = ANY(?)", key)
-	case "not_in":
-		res = fmt.Sprintf("NOT %s = ANY(?)", key)
-	case "between":
-		res = key + " BETWEEN ? AND ?"
-	case "not_between":
-		res = key + " NOT BETWEEN ? AND ?"
+	case "in", "not_in":
+		res, values, err = getInOperation(d, key, op == "not_in", value)
+		if err != nil {
+			err = newQueryError(err, attribute, op, value)
+		}
+		return
+	case "between", "not_between":
+		values, err = listValues(value)
+		if err != nil || len(values) != 2 {
+			err = newQueryError(ErrInvalidValue, attribute, op, value)
+			return
+		}
+
+		if op == "between" {
+			res = key + " BETWEEN ? AND ?"
+		} else {
+			res = key + " NOT BETWEEN ? AND ?"
+		}
+		return
 	case "starts_with":
-		res = key + " LIKE ? || '%'"
+		res = d.like(key, false, false, d.concat("?", "'%'"))
 	case "ends_with":
-		res = key + " LIKE '%' || ?"
+		res = d.like(key, false, false, d.concat("'%'", "?"))
 	case "substring":
-		res = key + " LIKE '%' || ? || '%'"
+		res = d.like(key, false, false, d.concat("'%'", "?", "'%'"))
 	case "i_starts_with":
-		res = key + " ILIKE ? || '%'"
+		res = d.like(key, false, true, d.concat("?", "'%'"))
 	case "i_ends_with":
-		res = key + " ILIKE '%' || ?"
+		res = d.like(key, false, true, d.concat("'%'", "?"))
 	case "i_substring":
-		res = key + " ILIKE '%' || ? || '%'"
+		res = d.like(key, false, true, d.concat("'%'", "?", "'%'"))
 	case "like":
-		res = key + " LIKE ?"
+		res = d.like(key, false, false, "?")
 	case "i_like":
-		res = key + " ILIKE ?"
+		res = d.like(key, false, true, "?")
 	case "not_like":
-		res = key + " NOT LIKE ?"
+		res = d.like(key, true, false, "?")
 	case "not_ilike":
-		res = key + " NOT ILIKE ?"
+		res = d.like(key, true, true, "?")
 	case "regexp":
-		res = key + " REGEXP/~ ?"
+		res = d.regexp(key, false, false)
 	case "not_regexp":
-		res = key + " NOT REGEXP/~ ?"
+		res = d.regexp(key, true, false)
 	case "i_regexp":
-		res = key + " NOT ~* ?"
+		res = d.regexp(key, false, true)
 	case "not_i_regexp":
-		res = key + " NOT !~* ?"
+		res = d.regexp(key, true, true)
 	default:
-		if value == nil {
-			res = fmt.Sprintf("%s IS NULL", key)
-		} else {
-			res = fmt.Sprintf("%s = ?", key)
+		res, values, err = "", nil, newQueryError(ErrInvalidOperator, attribute, op, nil)
+	}
+	return
+}
+
+func getInOperation(d dialect, key string, not bool, value interface{}) (res string, values []interface{}, err error) {
+	if !d.expandIn() {
+		res = fmt.Sprintf("%s = ANY(?)", key)
+		if not {
+			res = "NOT " + res
 		}
+		values = []interface{}{value}
+		return
+	}
+
+	values, err = listValues(value)
+	if err != nil {
+		return
+	}
+
+	if len(values) == 0 {
+		err = ErrEmptyInList
+		return
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	if not {
+		res = fmt.Sprintf("%s NOT IN (%s)", key, placeholders)
+	} else {
+		res = fmt.Sprintf("%s IN (%s)", key, placeholders)
 	}
 	return
 }
 
-func parseValueOperator(attribute string, val interface{}) (query string, values []interface{}, err error) {
+// listValues convert slice or array value into list of interface
+func listValues(value interface{}) (values []interface{}, err error) {
+	if list, ok := value.([]interface{}); ok {
+		return list, nil
+	}
+
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() != reflect.Slice && reflectValue.Kind() != reflect.Array {
+		err = ErrInvalidValue
+		return
+	}
+
+	values = make([]interface{}, 0, reflectValue.Len())
+	for i := 0; i < reflectValue.Len(); i++ {
+		values = append(values, reflectValue.Index(i).Interface())
+	}
+	return
+}
+
+// isListOperation operation which value is a list instead of multiple condition
+func isListOperation(op string) bool {
+	switch op {
+	case "in", "not_in", "between", "not_between":
+		return true
+	}
+	return false
+}
+
+func parseValueOperator(d dialect, attribute string, val interface{}) (query string, values []interface{}, err error) {
 	switch value := val.(type) {
 	case map[string]interface{}:
-		for key, v := range value {
-			if _, ok := v.([]interface{}); ok {
-				for _, arrVal := range v.([]interface{}) {
-					q := getOperation(key, attribute, arrVal)
+		for _, key := range sortedKeys(value) {
+			v := value[key]
+			if _, ok := v.([]interface{}); ok && !isListOperation(attribute) {
+				for i, arrVal := range v.([]interface{}) {
+					q, opValues, err := getOperation(d, key, attribute, arrVal)
 					if err != nil {
-						return query, values, err
+						return query, values, prependPath(err, key, strconv.Itoa(i))
 					}
 
 					if query == "" {
@@ -531,14 +1189,12 @@ func parseValueOperator(attribute string, val interface{}) (query string, values
 						query = fmt.Sprintf("(%s AND %s)", query, q)
 					}
 
-					if v != nil {
-						values = append(values, v)
-					}
+					values = append(values, opValues...)
 				}
 			} else {
-				q := getOperation(key, attribute, v)
+				q, opValues, err := getOperation(d, key, attribute, v)
 				if err != nil {
-					return query, values, err
+					return query, values, prependPath(err, key)
 				}
 
 				if query == "" {
@@ -547,13 +1203,11 @@ func parseValueOperator(attribute string, val interface{}) (query string, values
 					query = fmt.Sprintf("(%s AND %s)", query, q)
 				}
 
-				if v != nil {
-					values = append(values, v)
-				}
+				values = append(values, opValues...)
 			}
 		}
 	default:
-		q := getOperation(attribute, "=", val)
+		q, opValues, err := getOperation(d, attribute, "=", val)
 		if err != nil {
 			return query, values, err
 		}
@@ -563,77 +1217,46 @@ func parseValueOperator(attribute string, val interface{}) (query string, values
 			query = fmt.Sprintf("(%s AND %s)", query, q)
 		}
 
-		if val != nil {
-			values = append(values, val)
-		}
+		values = append(values, opValues...)
 	}
 
 	return
 }
 
-func parseBoolOperator(operator string, items map[string]interface{}) (query string, values []interface{}, err error) {
+func parseBoolOperator(d dialect, operator string, items map[string]interface{}) (query string, values []interface{}, err error) {
+	var joiner string
 	switch operator {
-	case "NOT":
-		for key, item := range items {
-			var q string
-			var v []interface{}
-			parseMap := make(map[string]interface{})
-			parseMap[key] = item
-			q, v, err = parseWhere(parseMap)
-			if err != nil {
-				return
-			}
-
-			if query == "" {
-				query = q
-			} else {
-				query = fmt.Sprintf("(%s OR %s)", query, q)
-			}
-
-			values = append(values, v...)
-		}
-		query = fmt.Sprintf("NOT (%s)", query)
+	case "NOT", "OR":
+		joiner = "OR"
 	case "AND":
-		for key, item := range items {
-			var q string
-			var v []interface{}
-			parseMap := make(map[string]interface{})
-			parseMap[key] = item
-			q, v, err = parseWhere(parseMap)
-			if err != nil {
-				return
-			}
+		joiner = "AND"
+	default:
+		err = newQueryError(ErrInvalidOperator, "", operator, nil)
+		return
+	}
 
-			if query == "" {
-				query = q
-			} else {
-				query = fmt.Sprintf("(%s AND %s)", query, q)
-			}
+	for _, key := range sortedKeys(items) {
+		item := items[key]
+		var q string
+		var v []interface{}
+		parseMap := make(map[string]interface{})
+		parseMap[key] = item
+		q, v, err = parseWhere(d, parseMap)
+		if err != nil {
+			return
+		}
 
-			values = append(values, v...)
+		if query == "" {
+			query = q
+		} else {
+			query = fmt.Sprintf("(%s %s %s)", query, joiner, q)
 		}
-	case "OR":
-		for key, item := range items {
-			var q string
-			var v []interface{}
-			parseMap := make(map[string]interface{})
-			parseMap[key] = item
-			q, v, err = parseWhere(parseMap)
-			if err != nil {
-				return
-			}
 
-			if query == "" {
-				query = q
-			} else {
-				query = fmt.Sprintf("(%s OR %s)", query, q)
-			}
+		values = append(values, v...)
+	}
 
-			values = append(values, v...)
-		}
-	default:
-		err = errors.New("invalid operator type")
-		return
+	if operator == "NOT" && query != "" {
+		query = fmt.Sprintf("NOT (%s)", query)
 	}
 	return
 }
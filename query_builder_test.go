@@ -0,0 +1,151 @@
+package goutils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func assertQuery(t *testing.T, name string, query string, values []interface{}, err error, expectedQuery string, expectedValues []interface{}) {
+	t.Helper()
+	if err != nil {
+		t.Errorf("%s: %v", name, err)
+		return
+	}
+
+	if query != expectedQuery {
+		t.Errorf("%s: expected query %q, got %q", name, expectedQuery, query)
+	}
+
+	if len(values) != 0 || len(expectedValues) != 0 {
+		if !reflect.DeepEqual(values, expectedValues) {
+			t.Errorf("%s: expected values %v, got %v", name, expectedValues, values)
+		}
+	}
+}
+
+func TestGetCountQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func(query QueryBuilderInteractor)
+		expected string
+		values   []interface{}
+	}{
+		{
+			name: "plain",
+			build: func(query QueryBuilderInteractor) {
+				query.AddSelection("id")
+				query.AddJoin(InnerJoin, "users", "u", "u.id = o.user_id")
+				query.AddWhere("o.status", "", "paid")
+				query.AddSort(DirDesc, "o.id")
+				query.AddPagination(NewPagination(2, 10))
+			},
+			expected: `SELECT COUNT(*) FROM "orders" "o" INNER JOIN "users" "u" ON "u"."id" = "o"."user_id" WHERE "o"."status" = $1`,
+			values:   []interface{}{"paid"},
+		},
+		{
+			name: "group",
+			build: func(query QueryBuilderInteractor) {
+				query.AddSelection("o.user_id")
+				query.AddGroup("o.user_id")
+				query.AddPagination(NewPagination(1, 10))
+			},
+			expected: `SELECT COUNT(*) FROM (SELECT "o"."user_id" FROM "orders" "o" GROUP BY "o"."user_id") "count_query"`,
+		},
+		{
+			name: "distinct",
+			build: func(query QueryBuilderInteractor) {
+				query.AddRawSelection(Raw("DISTINCT o.user_id"))
+			},
+			expected: `SELECT COUNT(*) FROM (SELECT DISTINCT o.user_id FROM "orders" "o") "count_query"`,
+		},
+		{
+			name: "aggregate",
+			build: func(query QueryBuilderInteractor) {
+				query.AddSum("o.amount", "total")
+				query.AddWhere("o.status", "", "paid")
+			},
+			expected: `SELECT COUNT(*) FROM (SELECT SUM("o"."amount") "total" FROM "orders" "o" WHERE "o"."status" = $1) "count_query"`,
+			values:   []interface{}{"paid"},
+		},
+		{
+			name: "aggregate selection",
+			build: func(query QueryBuilderInteractor) {
+				query.AddSelection("COUNT(*) total")
+			},
+			expected: `SELECT COUNT(*) FROM (SELECT COUNT(*) "total" FROM "orders" "o") "count_query"`,
+		},
+		{
+			name: "having",
+			build: func(query QueryBuilderInteractor) {
+				query.AddHaving("COUNT(*)", "gt", 5)
+			},
+			expected: `SELECT COUNT(*) FROM (SELECT * FROM "orders" "o" HAVING COUNT(*) > $1) "count_query"`,
+			values:   []interface{}{5},
+		},
+	}
+
+	for _, test := range tests {
+		query := NewQueryBuilder(WithDialect(PostgreSQL))
+		test.build(query)
+
+		res, values, err := query.GetCountQuery("orders", "o")
+		assertQuery(t, test.name, res, values, err, test.expected, test.values)
+	}
+}
+
+func TestRawWhereBoolOperatorList(t *testing.T) {
+	tests := map[string]string{
+		"AND": `SELECT * FROM "users" WHERE ("a" = $1 AND "b" = $2)`,
+		"OR":  `SELECT * FROM "users" WHERE ("a" = $1 OR "b" = $2)`,
+		"NOT": `SELECT * FROM "users" WHERE (NOT ("a" = $1) AND NOT ("b" = $2))`,
+	}
+
+	for operator, expected := range tests {
+		query := NewQueryBuilder(WithDialect(PostgreSQL))
+		query.AddRawWhere(map[string]interface{}{
+			operator: []map[string]interface{}{{"a": 1}, {"b": 2}},
+		})
+
+		res, values, err := query.GetQuery("users", "")
+		assertQuery(t, operator, res, values, err, expected, []interface{}{1, 2})
+	}
+}
+
+func TestAggregateSelection(t *testing.T) {
+	query := NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddSelection("user_id")
+	query.AddCountAll("total")
+	query.AddAvg("amount", "average")
+	query.AddMin("amount", "minimum")
+	query.AddMax("amount", "")
+	query.AddCountDistinct("product_id", "products")
+	query.AddGroup("user_id")
+	query.AddHaving("COUNT(*)", "gt", 5)
+	query.AddHaving("SUM(amount)", "lte", 1000)
+	query.AddWhere("status", "", "paid")
+
+	res, values, err := query.GetQuery("orders", "")
+	assertQuery(t, "aggregate", res, values, err,
+		`SELECT "user_id", COUNT(*) "total", AVG("amount") "average", MIN("amount") "minimum", MAX("amount"), COUNT(DISTINCT "product_id") "products" `+
+			`FROM "orders" WHERE "status" = $1 GROUP BY "user_id" HAVING (COUNT(*) > $2 AND SUM("amount") <= $3)`,
+		[]interface{}{"paid", 5, 1000},
+	)
+}
+
+func TestStringAgg(t *testing.T) {
+	tests := map[dialect]string{
+		PostgreSQL: `SELECT "user_id", STRING_AGG("name", ', ') "names" FROM "orders" GROUP BY "user_id"`,
+		MySQL:      "SELECT `user_id`, GROUP_CONCAT(`name` SEPARATOR ', ') `names` FROM `orders` GROUP BY `user_id`",
+		SQLite:     `SELECT "user_id", GROUP_CONCAT("name", ', ') "names" FROM "orders" GROUP BY "user_id"`,
+	}
+
+	for d, expected := range tests {
+		query := NewQueryBuilder(WithDialect(d))
+		query.AddSelection("user_id")
+		query.AddStringAgg("name", ", ", "names")
+		query.AddGroup("user_id")
+
+		res, values, err := query.GetQuery("orders", "")
+		assertQuery(t, d.name, res, values, err, expected, nil)
+	}
+}
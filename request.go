@@ -2,6 +2,7 @@ package goutils
 
 import (
 	"errors"
+	"fmt"
 	"math"
 )
 
@@ -19,6 +20,19 @@ type Pagination struct {
 	TotalPages  int `json:"total_pages"`
 }
 
+// MetaCursorResponse meta cursor pagination response
+type MetaCursorResponse struct {
+	Data CursorPagination `json:"pagination"`
+}
+
+// CursorPagination cursor pagination attributes
+type CursorPagination struct {
+	Count      int    `json:"count"`
+	PerPage    int    `json:"per_page"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
 // MapMetaResponse map meta pagination response
 func MapMetaResponse(totalCount int, currentPageCount int, currentPage int, limitPerPage int) MetaResponse {
 	totalPagesCount := math.Ceil(float64(totalCount) / float64(limitPerPage))
@@ -33,6 +47,18 @@ func MapMetaResponse(totalCount int, currentPageCount int, currentPage int, limi
 	}
 }
 
+// MapMetaCursorResponse map meta cursor pagination response, cursors are built with NextCursor and PrevCursor
+func MapMetaCursorResponse(currentPageCount int, limitPerPage int, nextCursor string, prevCursor string) MetaCursorResponse {
+	return MetaCursorResponse{
+		Data: CursorPagination{
+			Count:      currentPageCount,
+			PerPage:    limitPerPage,
+			NextCursor: nextCursor,
+			PrevCursor: prevCursor,
+		},
+	}
+}
+
 type paginationOption struct {
 	limit  int
 	page   int
@@ -69,16 +95,35 @@ func (pagination *paginationOption) GetPage() int {
 	return pagination.page
 }
 
+// SortableFields registry of sortable fields, map public api name to column, e.g. "createdAt": "u.created_at"
+type SortableFields map[string]string
+
+// UnknownSortFieldError error of sort field which is not registered in sortable fields
+type UnknownSortFieldError struct {
+	Field string
+}
+
+// Error error message of unknown sort field
+func (err *UnknownSortFieldError) Error() string {
+	return fmt.Sprintf("unknown sort field %s", err.Field)
+}
+
 // RequestOption pagination request option
 type RequestOption struct {
-	pagination *paginationOption
-	sortBy     *map[string]direction
+	pagination       *paginationOption
+	cursor           *cursorOption
+	sortBy           *map[string]direction
+	sortOrder        []string
+	sortableFields   SortableFields
+	filters          []filter
+	filterableFields FilterableFields
 }
 
 // NewRequestOption build new request option
 func NewRequestOption() *RequestOption {
 	return &RequestOption{
 		pagination: nil,
+		cursor:     nil,
 		sortBy:     nil,
 	}
 }
@@ -88,6 +133,11 @@ func (request *RequestOption) GetPagination() *paginationOption {
 	return request.pagination
 }
 
+// GetCursorPagination get cursor pagination request
+func (request *RequestOption) GetCursorPagination() *cursorOption {
+	return request.cursor
+}
+
 // GetSortBy get sort by request
 func (request *RequestOption) GetSortBy() *map[string]direction {
 	return request.sortBy
@@ -99,19 +149,55 @@ func (request *RequestOption) SetPagination(pagination *paginationOption) *Reque
 	return request
 }
 
-// SetSortBy set sort by request
+// SetCursorPagination set cursor pagination request, used instead of pagination when it is set
+func (request *RequestOption) SetCursorPagination(cursor *cursorOption) *RequestOption {
+	request.cursor = cursor
+	return request
+}
+
+// SetSortableFields set sortable fields registry, return UnknownSortFieldError when sort which is already set
+// is not in sortable fields, so the registry can be set before or after SetSortBy
+func (request *RequestOption) SetSortableFields(fields SortableFields) (*RequestOption, error) {
+	if fields != nil {
+		for _, val := range request.sortOrder {
+			if _, ok := fields[val]; !ok {
+				return nil, &UnknownSortFieldError{
+					Field: val,
+				}
+			}
+		}
+	}
+
+	request.sortableFields = fields
+	return request, nil
+}
+
+// SetSortBy set sort by request, return UnknownSortFieldError when field is not in sortable fields
 func (request *RequestOption) SetSortBy(sortDir direction, sortBy ...string) (*RequestOption, error) {
 	if sortDir.dir == "" {
 		err := errors.New("invalid sort type value")
 		return nil, err
 	}
 
+	if request.sortableFields != nil {
+		for _, val := range sortBy {
+			if _, ok := request.sortableFields[val]; !ok {
+				return nil, &UnknownSortFieldError{
+					Field: val,
+				}
+			}
+		}
+	}
+
 	sort := make(map[string]direction)
 	if request.sortBy != nil {
 		sort = *request.sortBy
 	}
 
 	for _, val := range sortBy {
+		if _, ok := sort[val]; !ok {
+			request.sortOrder = append(request.sortOrder, val)
+		}
 		sort[val] = sortDir
 	}
 	request.sortBy = &sort
@@ -124,19 +210,30 @@ func (request *RequestOption) SetPaginationWithSort(query QueryBuilderInteractor
 	var (
 		sortBy     = request.GetSortBy()
 		pagination = request.GetPagination()
+		cursor     = request.GetCursorPagination()
 	)
-	query.AddPagination(pagination)
-
-	if sortBy != nil {
-		for sort, dir := range *sortBy {
-			query.AddSort(dir, sort)
-		}
-	}
 
-	if pagination != nil {
+	if cursor != nil {
+		query.AddCursorPagination(cursor)
+		limit = cursor.GetLimit()
+	} else if pagination != nil {
+		query.AddPagination(pagination)
 		page = pagination.GetPage()
 		limit = pagination.GetLimit()
 	}
 
+	if sortBy != nil {
+		for _, sort := range request.sortOrder {
+			column := sort
+			if request.sortableFields != nil {
+				var ok bool
+				if column, ok = request.sortableFields[sort]; !ok {
+					continue
+				}
+			}
+			query.AddSort((*sortBy)[sort], column)
+		}
+	}
+
 	return query, page, limit
 }
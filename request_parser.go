@@ -0,0 +1,210 @@
+package goutils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var filterPattern = regexp.MustCompile(`^filter\[([^\[\]]+)\](?:\[([^\[\]]+)\])?$`)
+
+// =================================================
+
+// RequestParserOption option of request option parser
+type RequestParserOption struct {
+	DefaultPage      int
+	DefaultLimit     int
+	MaxLimit         int
+	DefaultSort      string
+	SortableFields   SortableFields
+	FilterableFields FilterableFields
+}
+
+// RequestOptionError error of request option parameter, can be reported back to client
+type RequestOptionError struct {
+	Parameter string `json:"parameter"`
+	Value     string `json:"value"`
+	Message   string `json:"message"`
+}
+
+// Error error message of request option parameter
+func (err *RequestOptionError) Error() string {
+	return fmt.Sprintf("invalid %s value %q: %s", err.Parameter, err.Value, err.Message)
+}
+
+// RequestOptionErrors list of request option error
+type RequestOptionErrors []*RequestOptionError
+
+// Error error message of request option parameters
+func (errs RequestOptionErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// =================================================
+
+// ParseRequestOption build request option from query of http request
+func ParseRequestOption(r *http.Request, option RequestParserOption) (*RequestOption, error) {
+	return ParseRequestOptionValues(r.URL.Query(), option)
+}
+
+// ParseRequestOptionValues build request option from url values,
+// pagination is read from page and limit or page[number] and page[size],
+// cursor pagination is used when cursor or page[cursor] is present,
+// sort is comma separated fields and prefix - means descending, e.g. sort=-created_at,name,
+// filter is read from filter[field] or filter[field][operation], e.g. filter[amount][gte]=100
+func ParseRequestOptionValues(values url.Values, option RequestParserOption) (*RequestOption, error) {
+	var (
+		errs    RequestOptionErrors
+		request = NewRequestOption()
+	)
+
+	page, err := parseIntValue(values, option.DefaultPage, "page", "page[number]")
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	limit, err := parseIntValue(values, option.DefaultLimit, "limit", "page[size]")
+	if err != nil {
+		errs = append(errs, err)
+	} else if option.MaxLimit > 0 && limit > option.MaxLimit {
+		errs = append(errs, &RequestOptionError{
+			Parameter: "limit",
+			Value:     strconv.Itoa(limit),
+			Message:   fmt.Sprintf("must not be greater than %d", option.MaxLimit),
+		})
+	}
+
+	if parameter, cursor, ok := getValue(values, "cursor", "page[cursor]"); ok {
+		cursorPagination, err := NewCursorPagination(cursor, limit)
+		if err != nil {
+			errs = append(errs, &RequestOptionError{
+				Parameter: parameter,
+				Value:     cursor,
+				Message:   err.Error(),
+			})
+		} else {
+			request.SetCursorPagination(cursorPagination)
+		}
+	} else {
+		request.SetPagination(NewPagination(page, limit))
+	}
+
+	if option.SortableFields != nil {
+		// request has no sort yet, so the registry is always accepted
+		_, _ = request.SetSortableFields(option.SortableFields)
+	}
+
+	sortBy := option.DefaultSort
+	if _, val, ok := getValue(values, "sort"); ok && val != "" {
+		sortBy = val
+	}
+
+	for _, field := range strings.Split(sortBy, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		dir := DirAsc
+		if strings.HasPrefix(field, "-") {
+			dir = DirDesc
+			field = strings.TrimPrefix(field, "-")
+		} else {
+			field = strings.TrimPrefix(field, "+")
+		}
+
+		if _, err := request.SetSortBy(dir, field); err != nil {
+			message := err.Error()
+			var unknown *UnknownSortFieldError
+			if errors.As(err, &unknown) {
+				message = "unknown sort field"
+			}
+
+			errs = append(errs, &RequestOptionError{
+				Parameter: "sort",
+				Value:     field,
+				Message:   message,
+			})
+		}
+	}
+
+	request.SetFilterableFields(option.FilterableFields)
+	for _, parameter := range sortedParameters(values) {
+		match := filterPattern.FindStringSubmatch(parameter)
+		if match == nil {
+			continue
+		}
+
+		value := values.Get(parameter)
+		if _, err := request.SetFilter(match[1], match[2], value); err != nil {
+			message := err.Error()
+			var filterErr *FilterError
+			if errors.As(err, &filterErr) {
+				message = filterErr.Message
+			}
+
+			errs = append(errs, &RequestOptionError{
+				Parameter: parameter,
+				Value:     value,
+				Message:   message,
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return request, nil
+}
+
+// sortedParameters parameters of url values in sorted order
+func sortedParameters(values url.Values) []string {
+	parameters := make([]string, 0, len(values))
+	for parameter := range values {
+		parameters = append(parameters, parameter)
+	}
+	sort.Strings(parameters)
+
+	return parameters
+}
+
+// getValue get first present value of parameters
+func getValue(values url.Values, parameters ...string) (parameter string, value string, ok bool) {
+	for _, parameter = range parameters {
+		if _, ok = values[parameter]; ok {
+			value = values.Get(parameter)
+			return
+		}
+	}
+
+	return "", "", false
+}
+
+func parseIntValue(values url.Values, defaultValue int, parameters ...string) (int, *RequestOptionError) {
+	parameter, value, ok := getValue(values, parameters...)
+	if !ok || value == "" {
+		return defaultValue, nil
+	}
+
+	result, err := strconv.Atoi(value)
+	if err != nil || result <= 0 {
+		return 0, &RequestOptionError{
+			Parameter: parameter,
+			Value:     value,
+			Message:   "must be a positive integer",
+		}
+	}
+
+	return result, nil
+}
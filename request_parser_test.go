@@ -0,0 +1,118 @@
+package goutils
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParseRequestOption(t *testing.T) {
+	r := httptest.NewRequest("GET", "/users?sort=-created_at,name&page[size]=20&page[number]=3&filter[age][gte]=18", nil)
+	request, err := ParseRequestOption(r, RequestParserOption{
+		MaxLimit:         50,
+		SortableFields:   SortableFields{"created_at": "u.created_at", "name": "u.name"},
+		FilterableFields: FilterableFields{"age": {Column: "u.age", Type: FilterInt}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pagination := request.GetPagination(); pagination.GetPage() != 3 || pagination.GetLimit() != 20 || pagination.GetOffset() != 40 {
+		t.Errorf("unexpected pagination %+v", pagination)
+	}
+
+	query, _, _ := request.SetPaginationWithSort(NewQueryBuilder(WithDialect(PostgreSQL)))
+	res, values, err := query.GetQuery("users", "u")
+	assertQuery(t, "sort", res, values, err,
+		`SELECT * FROM "users" "u" ORDER BY "u"."created_at" DESC, "u"."name" ASC LIMIT 20 OFFSET 40`, nil,
+	)
+}
+
+func TestParseRequestOptionDefault(t *testing.T) {
+	request, err := ParseRequestOptionValues(url.Values{}, RequestParserOption{
+		DefaultPage:  2,
+		DefaultLimit: 15,
+		DefaultSort:  "-id",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pagination := request.GetPagination(); pagination.GetPage() != 2 || pagination.GetLimit() != 15 {
+		t.Errorf("unexpected pagination %+v", pagination)
+	}
+
+	if sortBy := request.GetSortBy(); sortBy == nil || (*sortBy)["id"] != DirDesc {
+		t.Errorf("expected default sort, got %v", sortBy)
+	}
+
+	request, err = ParseRequestOptionValues(url.Values{"page": {"4"}, "limit": {"5"}, "sort": {"name"}}, RequestParserOption{
+		DefaultSort: "-id",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pagination := request.GetPagination(); pagination.GetPage() != 4 || pagination.GetLimit() != 5 {
+		t.Errorf("unexpected pagination %+v", pagination)
+	}
+
+	if sortBy := *request.GetSortBy(); len(sortBy) != 1 || sortBy["name"] != DirAsc {
+		t.Errorf("expected sort to replace default sort, got %v", sortBy)
+	}
+}
+
+func TestParseRequestOptionCursor(t *testing.T) {
+	for _, parameter := range []string{"cursor", "page[cursor]"} {
+		request, err := ParseRequestOptionValues(url.Values{parameter: {NextCursor(int64(10))}, "limit": {"5"}}, RequestParserOption{})
+		if err != nil {
+			t.Fatalf("%s: %v", parameter, err)
+		}
+
+		cursor := request.GetCursorPagination()
+		if cursor == nil || cursor.GetLimit() != 5 || request.GetPagination() != nil {
+			t.Errorf("%s: expected cursor pagination, got %+v", parameter, cursor)
+		}
+	}
+
+	_, err := ParseRequestOptionValues(url.Values{"page[cursor]": {"invalid"}}, RequestParserOption{})
+	var errs RequestOptionErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Parameter != "page[cursor]" {
+		t.Errorf("expected error of page[cursor], got %v", err)
+	}
+}
+
+func TestParseRequestOptionErrors(t *testing.T) {
+	_, err := ParseRequestOptionValues(url.Values{
+		"page[number]":      {"0"},
+		"page[size]":        {"100"},
+		"sort":              {"-password"},
+		"filter[age][like]": {"1"},
+	}, RequestParserOption{
+		MaxLimit:         50,
+		SortableFields:   SortableFields{"created_at": "created_at"},
+		FilterableFields: FilterableFields{"age": {Column: "age", Type: FilterInt}},
+	})
+
+	var errs RequestOptionErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected RequestOptionErrors, got %v", err)
+	}
+
+	expected := []RequestOptionError{
+		{Parameter: "page[number]", Value: "0"},
+		{Parameter: "limit", Value: "100"},
+		{Parameter: "sort", Value: "password", Message: "unknown sort field"},
+		{Parameter: "filter[age][like]", Value: "1"},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), errs)
+	}
+
+	for i, val := range expected {
+		if errs[i].Parameter != val.Parameter || errs[i].Value != val.Value || (val.Message != "" && errs[i].Message != val.Message) {
+			t.Errorf("expected error %+v, got %+v", val, *errs[i])
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package goutils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetSortableFieldsAfterSortBy(t *testing.T) {
+	request := NewRequestOption()
+	if _, err := request.SetSortBy(DirDesc, "createdAt", "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := request.SetSortableFields(SortableFields{"createdAt": "created_at"})
+	var unknown *UnknownSortFieldError
+	if !errors.As(err, &unknown) || unknown.Field != "password" {
+		t.Fatalf("expected UnknownSortFieldError for password, got %v", err)
+	}
+
+	request = NewRequestOption()
+	if _, err = request.SetSortBy(DirDesc, "createdAt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = request.SetSortableFields(SortableFields{"createdAt": "created_at"}); err != nil {
+		t.Fatal(err)
+	}
+
+	query, _, _ := request.SetPaginationWithSort(NewQueryBuilder(WithDialect(PostgreSQL)))
+	res, values, err := query.GetQuery("users", "")
+	assertQuery(t, "sort", res, values, err, `SELECT * FROM "users" ORDER BY "created_at" DESC`, nil)
+}
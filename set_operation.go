@@ -0,0 +1,85 @@
+package goutils
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// =================================================
+
+// setOperation type of set operation
+type setOperation struct {
+	operation string
+}
+
+var (
+	// Union union set operation, duplicate rows are removed
+	Union = setOperation{
+		operation: "UNION",
+	}
+
+	// UnionAll union all set operation
+	UnionAll = setOperation{
+		operation: "UNION ALL",
+	}
+
+	// Intersect intersect set operation
+	Intersect = setOperation{
+		operation: "INTERSECT",
+	}
+
+	// Except except set operation
+	Except = setOperation{
+		operation: "EXCEPT",
+	}
+)
+
+type compound struct {
+	operation string
+	subquery  subquery
+}
+
+// AddSetOperation combine query with subquery, sort and pagination of the query builder are applied to the combined result.
+// Cursor pagination is not supported on combined result
+func (q *queryBuilder) AddSetOperation(operation setOperation, subquery subquery) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	arrCompound := make([]compound, 0)
+	if q.compound != nil {
+		arrCompound = append(arrCompound, *q.compound...)
+	}
+
+	arrCompound = append(arrCompound, compound{
+		operation: operation.operation,
+		subquery:  subquery,
+	})
+	q.compound = &arrCompound
+	q.addKey(operation.operation, subquery)
+}
+
+func parseCompound(d dialect, arrCompound []compound) (query string, values []interface{}, err error) {
+	for i, val := range arrCompound {
+		if val.operation == "" {
+			err = prependPath(newQueryError(ErrInvalidOperator, val.subquery.tablename, "", nil), "set_operation", strconv.Itoa(i))
+			return
+		}
+
+		var (
+			q string
+			v []interface{}
+		)
+		q, v, err = val.subquery.parseBranch(d)
+		if err != nil {
+			return query, values, prependPath(err, "set_operation", strconv.Itoa(i))
+		}
+
+		if query != "" {
+			query = fmt.Sprintf("%s %s %s", query, val.operation, q)
+		} else {
+			query = fmt.Sprintf("%s %s", val.operation, q)
+		}
+		values = append(values, v...)
+	}
+	return
+}
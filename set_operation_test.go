@@ -0,0 +1,87 @@
+package goutils
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSetOperationBranchIsSafeForConcurrentUse(t *testing.T) {
+	branch := NewQueryBuilder(WithDialect(PostgreSQL))
+	branch.AddSelection("id")
+
+	query := NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddSelection("id")
+	query.AddSetOperation(Union, Subquery(branch, "archived_users", ""))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			branch.AddSort(DirAsc, "id")
+		}()
+		go func() {
+			defer wg.Done()
+			if _, _, err := query.GetQuery("users", ""); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	res, _, err := query.GetQuery("users", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := `SELECT "id" FROM "users" UNION (SELECT "id" FROM "archived_users" ORDER BY "id" ASC`; !strings.HasPrefix(res, expected) {
+		t.Errorf("expected prefix %q, got %q", expected, res)
+	}
+}
+
+func TestSetOperationQuery(t *testing.T) {
+	tests := []struct {
+		dialect  dialect
+		expected string
+	}{
+		{PostgreSQL, `SELECT "id" FROM "users" WHERE "active" = $1 UNION ALL (SELECT "id" FROM "archived_users" WHERE "year" = $2 ORDER BY "id" DESC LIMIT 5 OFFSET 0) EXCEPT SELECT "id" FROM "banned_users" ORDER BY "id" ASC LIMIT 10 OFFSET 0`},
+		{MySQL, "SELECT `id` FROM `users` WHERE `active` = ? UNION ALL (SELECT `id` FROM `archived_users` WHERE `year` = ? ORDER BY `id` DESC LIMIT 5 OFFSET 0) EXCEPT SELECT `id` FROM `banned_users` ORDER BY `id` ASC LIMIT 10 OFFSET 0"},
+		{SQLite, `SELECT "id" FROM "users" WHERE "active" = ? UNION ALL SELECT * FROM (SELECT "id" FROM "archived_users" WHERE "year" = ? ORDER BY "id" DESC LIMIT 5 OFFSET 0) EXCEPT SELECT "id" FROM "banned_users" ORDER BY "id" ASC LIMIT 10 OFFSET 0`},
+	}
+
+	for _, test := range tests {
+		archived := NewQueryBuilder()
+		archived.AddSelection("id")
+		archived.AddWhere("year", "", 2020)
+		archived.AddSort(DirDesc, "id")
+		archived.AddPagination(NewPagination(1, 5))
+
+		banned := NewQueryBuilder()
+		banned.AddSelection("id")
+
+		query := NewQueryBuilder(WithDialect(test.dialect))
+		query.AddSelection("id")
+		query.AddWhere("active", "", true)
+		query.AddSetOperation(UnionAll, Subquery(archived, "archived_users", ""))
+		query.AddSetOperation(Except, Subquery(banned, "banned_users", ""))
+		query.AddSort(DirAsc, "id")
+		query.AddPagination(NewPagination(1, 10))
+
+		res, values, err := query.GetQuery("users", "")
+		assertQuery(t, test.dialect.name, res, values, err, test.expected, []interface{}{true, 2020})
+	}
+}
+
+func TestSetOperationWithCursorIsRejected(t *testing.T) {
+	cursor, _ := NewCursorPagination("", 10)
+	query := NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddSetOperation(Intersect, Subquery(NewQueryBuilder(), "admins", ""))
+	query.AddSort(DirAsc, "id")
+	query.AddCursorPagination(cursor)
+
+	if res, _, err := query.GetQuery("users", ""); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("expected ErrInvalidValue, got %q %v", res, err)
+	}
+}
@@ -0,0 +1,161 @@
+package goutils
+
+import (
+	"fmt"
+)
+
+// =================================================
+
+// expression sql expression with its values, parsed with ? placeholder
+type expression interface {
+	parse(d dialect) (query string, values []interface{}, err error)
+}
+
+// aliasedExpression expression with aliases
+type aliasedExpression struct {
+	expression expression
+	aliases    string
+}
+
+func (a aliasedExpression) parse(d dialect) (query string, values []interface{}, err error) {
+	query, values, err = a.expression.parse(d)
+	if err != nil || a.aliases == "" {
+		return
+	}
+
+	aliases, err := d.identifier(a.aliases)
+	if err != nil {
+		return
+	}
+
+	query = fmt.Sprintf("%s %s", query, aliases)
+	return
+}
+
+// =================================================
+
+// subquery query builder nested into another query
+type subquery struct {
+	query     QueryBuilderInteractor
+	tablename string
+	aliases   string
+}
+
+// Subquery create subquery from query builder, to be used as where value, selection or from source.
+// Subquery is parsed with the dialect of the outer query builder
+func Subquery(query QueryBuilderInteractor, tablename string, aliases string) subquery {
+	return subquery{
+		query:     query,
+		tablename: tablename,
+		aliases:   aliases,
+	}
+}
+
+func (s subquery) parse(d dialect) (query string, values []interface{}, err error) {
+	query, values, err = s.parseQuery(d)
+	if err != nil {
+		return
+	}
+
+	query = fmt.Sprintf("(%s)", query)
+	return
+}
+
+// parseQuery parse subquery without parentheses
+func (s subquery) parseQuery(d dialect) (query string, values []interface{}, err error) {
+	builder, ok := s.query.(*queryBuilder)
+	if !ok {
+		err = newQueryError(ErrInvalidValue, s.tablename, "", nil)
+		return
+	}
+
+	builder.mu.RLock()
+	defer builder.mu.RUnlock()
+
+	return builder.parseQuery(d, s.tablename, s.aliases)
+}
+
+// parseBranch parse subquery as branch of set operation, it is wrapped in parentheses when it has sort or limit,
+// or in SELECT * FROM (...) for sqlite which does not accept parenthesized branch.
+// Sort and limit are read under the lock of subquery builder
+func (s subquery) parseBranch(d dialect) (query string, values []interface{}, err error) {
+	builder, ok := s.query.(*queryBuilder)
+	if !ok {
+		err = newQueryError(ErrInvalidValue, s.tablename, "", nil)
+		return
+	}
+
+	builder.mu.RLock()
+	defer builder.mu.RUnlock()
+
+	query, values, err = builder.parseQuery(d, s.tablename, s.aliases)
+	if err != nil {
+		return
+	}
+
+	if builder.sort == nil && builder.pagination == nil && builder.cursor == nil {
+		return
+	}
+
+	if d == SQLite {
+		query = fmt.Sprintf("SELECT * FROM (%s)", query)
+	} else {
+		query = fmt.Sprintf("(%s)", query)
+	}
+	return
+}
+
+// String subquery cache key
+func (s subquery) String() string {
+	if s.query == nil {
+		return s.tablename
+	}
+
+	return fmt.Sprintf("(%s:%s)", s.tablename, s.query.GetKey())
+}
+
+// =================================================
+
+// AddFrom add subquery as from source, tablename of GetQuery is ignored and aliases is used as derived table aliases
+func (q *queryBuilder) AddFrom(subquery subquery) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.from = &subquery
+	q.addKey("from", subquery)
+}
+
+// getExpressionOperation operation with subquery or raw expression as value
+func getExpressionOperation(d dialect, key string, op string, value expression) (res string, values []interface{}, err error) {
+	var sub string
+	sub, values, err = value.parse(d)
+	if err != nil {
+		return
+	}
+
+	switch op {
+	case "in":
+		res = fmt.Sprintf("%s IN %s", key, sub)
+	case "not_in":
+		res = fmt.Sprintf("%s NOT IN %s", key, sub)
+	case "exists":
+		res = fmt.Sprintf("EXISTS %s", sub)
+	case "not_exists":
+		res = fmt.Sprintf("NOT EXISTS %s", sub)
+	case "lte", "<=":
+		res = fmt.Sprintf("%s <= %s", key, sub)
+	case "lt", "<":
+		res = fmt.Sprintf("%s < %s", key, sub)
+	case "gte", ">=":
+		res = fmt.Sprintf("%s >= %s", key, sub)
+	case "gt", ">":
+		res = fmt.Sprintf("%s > %s", key, sub)
+	case "neq", "!=":
+		res = fmt.Sprintf("%s != %s", key, sub)
+	case "", "eq", "=":
+		res = fmt.Sprintf("%s = %s", key, sub)
+	default:
+		err = newQueryError(ErrInvalidOperator, key, op, nil)
+	}
+	return
+}
@@ -0,0 +1,86 @@
+package goutils
+
+import (
+	"testing"
+)
+
+func newOrderSubquery() QueryBuilderInteractor {
+	orders := NewQueryBuilder()
+	orders.AddSelection("o.user_id")
+	orders.AddWhere("o.amount", "gt", 100)
+	orders.AddWhere("o.status", "", "paid")
+	return orders
+}
+
+func TestSubqueryPlaceholder(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func(query QueryBuilderInteractor)
+		expected string
+		values   []interface{}
+	}{
+		{
+			name: "in",
+			build: func(query QueryBuilderInteractor) {
+				query.AddWhere("u.active", "", true)
+				query.AddWhere("u.id", "in", Subquery(newOrderSubquery(), "orders", "o"))
+				query.AddWhere("u.age", "gte", 18)
+			},
+			expected: `SELECT * FROM "users" "u" WHERE (("u"."active" = $1 AND "u"."id" IN (SELECT "o"."user_id" FROM "orders" "o" WHERE ("o"."amount" > $2 AND "o"."status" = $3))) AND "u"."age" >= $4)`,
+			values:   []interface{}{true, 100, "paid", 18},
+		},
+		{
+			name: "not in",
+			build: func(query QueryBuilderInteractor) {
+				query.AddCondition(NotIn("u.id", Subquery(newOrderSubquery(), "orders", "o")))
+			},
+			expected: `SELECT * FROM "users" "u" WHERE "u"."id" NOT IN (SELECT "o"."user_id" FROM "orders" "o" WHERE ("o"."amount" > $1 AND "o"."status" = $2))`,
+			values:   []interface{}{100, "paid"},
+		},
+		{
+			name: "exists",
+			build: func(query QueryBuilderInteractor) {
+				orders := newOrderSubquery()
+				orders.AddWhereExpression(Raw(`"o"."user_id" = "u"."id"`))
+
+				query.AddWhere("u.active", "", true)
+				query.AddCondition(Exists(Subquery(orders, "orders", "o")))
+			},
+			expected: `SELECT * FROM "users" "u" WHERE ("u"."active" = $1 AND EXISTS (SELECT "o"."user_id" FROM "orders" "o" WHERE (("o"."amount" > $2 AND "o"."status" = $3) AND "o"."user_id" = "u"."id")))`,
+			values:   []interface{}{true, 100, "paid"},
+		},
+		{
+			name: "from",
+			build: func(query QueryBuilderInteractor) {
+				query.AddSelection("u.user_id")
+				query.AddFrom(Subquery(newOrderSubquery(), "orders", "o"))
+				query.AddWhere("u.user_id", "neq", 7)
+			},
+			expected: `SELECT "u"."user_id" FROM (SELECT "o"."user_id" FROM "orders" "o" WHERE ("o"."amount" > $1 AND "o"."status" = $2)) "u" WHERE "u"."user_id" != $3`,
+			values:   []interface{}{100, "paid", 7},
+		},
+		{
+			name: "selection",
+			build: func(query QueryBuilderInteractor) {
+				total := NewQueryBuilder()
+				total.AddCountAll("")
+				total.AddWhere("o.amount", "gt", 100)
+				total.AddWhereExpression(Raw(`"o"."user_id" = "u"."id"`))
+
+				query.AddSelection("u.id")
+				query.AddSubquerySelection(Subquery(total, "orders", "o"), "total")
+				query.AddWhere("u.active", "", true)
+			},
+			expected: `SELECT "u"."id", (SELECT COUNT(*) FROM "orders" "o" WHERE ("o"."amount" > $1 AND "o"."user_id" = "u"."id")) "total" FROM "users" "u" WHERE "u"."active" = $2`,
+			values:   []interface{}{100, true},
+		},
+	}
+
+	for _, test := range tests {
+		query := NewQueryBuilder(WithDialect(PostgreSQL))
+		test.build(query)
+
+		res, values, err := query.GetQuery("users", "u")
+		assertQuery(t, test.name, res, values, err, test.expected, test.values)
+	}
+}
@@ -0,0 +1,179 @@
+package goutils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// =================================================
+
+// UpdateBuilderInteractor update query builder interactor
+type UpdateBuilderInteractor interface {
+	GetQuery(tablename string) (query string, values []interface{}, err error)
+	AddSet(column string, value interface{})
+	AddSetData(data interface{})
+	AddSetExpression(column string, expression rawExpression)
+	AddWhere(attribute string, operation string, value interface{})
+	AddRawWhere(listWhere map[string]interface{})
+	AddCondition(condition ...Condition)
+	AllowEmptyWhere()
+}
+
+type updateBuilder struct {
+	set             *[]assignment
+	where           *[]map[string]interface{}
+	allowEmptyWhere bool
+	option          builderOption
+}
+
+type assignment struct {
+	column     string
+	expression string
+	values     []interface{}
+	data       interface{}
+}
+
+// NewUpdateBuilder create new update query builder
+func NewUpdateBuilder(opts ...QueryBuilderOption) UpdateBuilderInteractor {
+	return &updateBuilder{
+		set:    nil,
+		where:  nil,
+		option: newBuilderOption(opts...),
+	}
+}
+
+// GetQuery parse update query
+func (q *updateBuilder) GetQuery(tablename string) (query string, values []interface{}, err error) {
+	var (
+		d     = q.option.dialect
+		set   string
+		where string
+	)
+
+	if q.set == nil {
+		err = prependPath(newQueryError(ErrInvalidValue, tablename, "", nil), "set")
+		return
+	}
+
+	set, values, err = parseAssignment(d, *q.set)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var table string
+	if table, err = d.identifier(tablename); err != nil {
+		return "", nil, err
+	}
+
+	if q.where != nil {
+		var whereValues []interface{}
+		if where, whereValues, err = parseWhereList(d, "where", *q.where); err != nil {
+			return "", nil, err
+		}
+		values = append(values, whereValues...)
+	}
+
+	// where which is rendered empty, e.g. AddRawWhere of empty map, is treated as no where
+	if where == "" && !q.allowEmptyWhere {
+		err = fmt.Errorf("%w: update without where is not allowed, use AllowEmptyWhere to update all rows", ErrEmptyWhere)
+		return "", nil, err
+	}
+
+	query = fmt.Sprintf(`UPDATE %s SET %s`, table, set)
+	if where != "" {
+		query = fmt.Sprintf(`%s WHERE %s`, query, where)
+	}
+
+	query = d.rebind(query)
+	return
+}
+
+// AllowEmptyWhere allow update query without where, which update all rows of table
+func (q *updateBuilder) AllowEmptyWhere() {
+	q.allowEmptyWhere = true
+}
+
+// =================================================
+
+// AddSet add set column with value
+func (q *updateBuilder) AddSet(column string, value interface{}) {
+	q.AddSetExpression(column, Raw("?", value))
+}
+
+// AddSetData add set columns from struct or map[string]interface{}
+func (q *updateBuilder) AddSetData(data interface{}) {
+	q.set = appendAssignment(q.set, assignment{
+		data: data,
+	})
+}
+
+// AddSetExpression add set column with raw sql expression, e.g. Raw("count + ?", 1) or Raw("NOW()")
+func (q *updateBuilder) AddSetExpression(column string, expression rawExpression) {
+	q.set = appendAssignment(q.set, assignment{
+		column:     column,
+		expression: expression.query,
+		values:     expression.values,
+	})
+}
+
+func appendAssignment(listSet *[]assignment, set assignment) *[]assignment {
+	arrSet := make([]assignment, 0)
+	if listSet != nil {
+		arrSet = append(arrSet, *listSet...)
+	}
+
+	arrSet = append(arrSet, set)
+	return &arrSet
+}
+
+func parseAssignment(d dialect, listSet []assignment) (query string, values []interface{}, err error) {
+	arrSet := make([]string, 0, len(listSet))
+	for i, set := range listSet {
+		path := []string{"set", strconv.Itoa(i)}
+		if set.data == nil {
+			var column string
+			if column, err = d.identifier(set.column); err != nil {
+				return query, values, prependPath(err, path...)
+			}
+			arrSet = append(arrSet, fmt.Sprintf("%s = %s", column, set.expression))
+			values = append(values, set.values...)
+			continue
+		}
+
+		var row map[string]interface{}
+		row, err = parseDataRow(d, set.data)
+		if err != nil {
+			return query, values, prependPath(err, path...)
+		}
+
+		for _, key := range sortedKeys(row) {
+			var column string
+			if column, err = d.identifier(key); err != nil {
+				return query, values, prependPath(err, path...)
+			}
+			arrSet = append(arrSet, fmt.Sprintf("%s = ?", column))
+			values = append(values, row[key])
+		}
+	}
+
+	query = strings.Join(arrSet, ", ")
+	return
+}
+
+// =================================================
+
+// AddWhere add where query, using the same condition grammar as query builder
+func (q *updateBuilder) AddWhere(attribute string, operation string, value interface{}) {
+	q.where = appendWhere(q.where, buildWhere(attribute, operation, value))
+}
+
+// AddRawWhere add raw where query
+func (q *updateBuilder) AddRawWhere(listWhere map[string]interface{}) {
+	where := make(map[string]interface{})
+	for key, val := range listWhere {
+		where[key] = val
+	}
+
+	q.where = appendWhere(q.where, where)
+}
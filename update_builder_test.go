@@ -0,0 +1,63 @@
+package goutils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUpdateQuery(t *testing.T) {
+	query := NewUpdateBuilder(WithDialect(PostgreSQL))
+	query.AddSet("name", "john")
+	query.AddSetExpression("visit", Raw("visit + ?", 1))
+	query.AddWhere("id", "", 10)
+
+	res, values, err := query.GetQuery("users")
+	assertQuery(t, "update", res, values, err,
+		`UPDATE "users" SET "name" = $1, "visit" = visit + $2 WHERE "id" = $3`,
+		[]interface{}{"john", 1, 10},
+	)
+
+	query = NewUpdateBuilder(WithDialect(MySQL))
+	query.AddSetData(map[string]interface{}{"name": "john", "age": 20})
+	query.AddWhere("id", "in", []int{1, 2})
+
+	res, values, err = query.GetQuery("users")
+	assertQuery(t, "update data", res, values, err,
+		"UPDATE `users` SET `age` = ?, `name` = ? WHERE `id` IN (?, ?)",
+		[]interface{}{20, "john", 1, 2},
+	)
+}
+
+func TestUpdateQueryWithoutWhere(t *testing.T) {
+	tests := map[string][]map[string]interface{}{
+		"no where":           nil,
+		"empty raw where":    {{}},
+		"empty AND list":     {{"AND": []map[string]interface{}{}}},
+		"empty NOT operator": {{"NOT": map[string]interface{}{}}},
+	}
+
+	for name, listWhere := range tests {
+		query := NewUpdateBuilder(WithDialect(PostgreSQL))
+		query.AddSet("name", "john")
+		for _, where := range listWhere {
+			query.AddRawWhere(where)
+		}
+
+		res, values, err := query.GetQuery("users")
+		if !errors.Is(err, ErrEmptyWhere) {
+			t.Errorf("%s: expected ErrEmptyWhere, got %v", name, err)
+		}
+
+		if res != "" || values != nil {
+			t.Errorf("%s: expected empty query, got %q %v", name, res, values)
+		}
+	}
+
+	query := NewUpdateBuilder(WithDialect(PostgreSQL))
+	query.AddSet("name", "john")
+	query.AddRawWhere(map[string]interface{}{})
+	query.AllowEmptyWhere()
+
+	res, values, err := query.GetQuery("users")
+	assertQuery(t, "allow empty where", res, values, err, `UPDATE "users" SET "name" = $1`, []interface{}{"john"})
+}
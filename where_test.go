@@ -0,0 +1,35 @@
+package goutils
+
+import (
+	"testing"
+)
+
+func TestWhereIsDeterministic(t *testing.T) {
+	expected := `SELECT * FROM "users" WHERE ((("a" = $1 AND "b" > $2) AND "c" < $3) AND ("d" = $4 OR "e" = $5))`
+	for i := 0; i < 50; i++ {
+		query := NewQueryBuilder(WithDialect(PostgreSQL))
+		query.AddRawWhere(map[string]interface{}{
+			"a":  1,
+			"gt": map[string]interface{}{"b": 2},
+			"lt": map[string]interface{}{"c": 3},
+		})
+		query.AddRawWhere(map[string]interface{}{
+			"OR": map[string]interface{}{"d": 4, "e": 5},
+		})
+
+		res, values, err := query.GetQuery("users", "")
+		assertQuery(t, "deterministic", res, values, err, expected, []interface{}{1, 2, 3, 4, 5})
+		if t.Failed() {
+			return
+		}
+	}
+}
+
+func TestWhereSameOperatorIsKept(t *testing.T) {
+	query := NewQueryBuilder(WithDialect(PostgreSQL))
+	query.AddWhere("a", "gt", 1)
+	query.AddWhere("b", "gt", 2)
+
+	res, values, err := query.GetQuery("users", "")
+	assertQuery(t, "same operator", res, values, err, `SELECT * FROM "users" WHERE ("a" > $1 AND "b" > $2)`, []interface{}{1, 2})
+}
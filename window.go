@@ -0,0 +1,179 @@
+package goutils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// =================================================
+
+// windowFunction window function of selection
+type windowFunction struct {
+	function string
+	column   string
+	offset   *int
+}
+
+// RowNumber ROW_NUMBER() window function
+func RowNumber() windowFunction {
+	return windowFunction{
+		function: "ROW_NUMBER",
+	}
+}
+
+// Rank RANK() window function
+func Rank() windowFunction {
+	return windowFunction{
+		function: "RANK",
+	}
+}
+
+// DenseRank DENSE_RANK() window function
+func DenseRank() windowFunction {
+	return windowFunction{
+		function: "DENSE_RANK",
+	}
+}
+
+// Lag LAG(column, offset) window function, value of previous row
+func Lag(column string, offset int) windowFunction {
+	return windowFunction{
+		function: "LAG",
+		column:   column,
+		offset:   &offset,
+	}
+}
+
+// Lead LEAD(column, offset) window function, value of next row
+func Lead(column string, offset int) windowFunction {
+	return windowFunction{
+		function: "LEAD",
+		column:   column,
+		offset:   &offset,
+	}
+}
+
+// RunningSum SUM(column) window function
+func RunningSum(column string) windowFunction {
+	return windowFunction{
+		function: "SUM",
+		column:   column,
+	}
+}
+
+// RunningCount COUNT(column) window function
+func RunningCount(column string) windowFunction {
+	return windowFunction{
+		function: "COUNT",
+		column:   column,
+	}
+}
+
+// =================================================
+
+// windowOption over clause of window function
+type windowOption struct {
+	partitionBy []string
+	orderBy     []sortItem
+}
+
+// NewWindow build new window option
+func NewWindow() *windowOption {
+	return &windowOption{
+		partitionBy: nil,
+		orderBy:     nil,
+	}
+}
+
+// PartitionBy add partition by columns
+func (window *windowOption) PartitionBy(column ...string) *windowOption {
+	window.partitionBy = append(window.partitionBy, column...)
+	return window
+}
+
+// OrderBy add order by columns, same as AddSort
+func (window *windowOption) OrderBy(direction direction, sortBy ...string) *windowOption {
+	for _, val := range sortBy {
+		window.orderBy = append(window.orderBy, sortItem{
+			column:    val,
+			direction: direction,
+		})
+	}
+	return window
+}
+
+// =================================================
+
+// windowExpression window function with over clause
+type windowExpression struct {
+	function windowFunction
+	window   windowOption
+}
+
+// AddWindowFunction add window function selection, e.g. ROW_NUMBER() OVER (PARTITION BY ... ORDER BY ...) aliases
+func (q *queryBuilder) AddWindowFunction(function windowFunction, window *windowOption, aliases string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if window == nil {
+		window = NewWindow()
+	}
+
+	q.addSelection(aliasedExpression{
+		expression: windowExpression{
+			function: function,
+			window: windowOption{
+				partitionBy: append([]string{}, window.partitionBy...),
+				orderBy:     append([]sortItem{}, window.orderBy...),
+			},
+		},
+		aliases: aliases,
+	})
+}
+
+func (w windowExpression) parse(d dialect) (query string, values []interface{}, err error) {
+	if w.function.function == "" {
+		err = newQueryError(ErrInvalidOperator, w.function.column, "", nil)
+		return
+	}
+
+	var args []string
+	if w.function.column != "" {
+		var column string
+		if column, err = d.column(w.function.column, false); err != nil {
+			return
+		}
+		args = append(args, column)
+	}
+
+	if w.function.offset != nil {
+		args = append(args, fmt.Sprintf("%d", *w.function.offset))
+	}
+
+	var over []string
+	if len(w.window.partitionBy) > 0 {
+		partitionBy := make([]string, 0, len(w.window.partitionBy))
+		for _, val := range w.window.partitionBy {
+			var column string
+			if column, err = d.column(val, false); err != nil {
+				return
+			}
+			partitionBy = append(partitionBy, column)
+		}
+		over = append(over, fmt.Sprintf("PARTITION BY %s", strings.Join(partitionBy, ", ")))
+	}
+
+	if len(w.window.orderBy) > 0 {
+		var sort string
+		sort, err = parseSort(d, w.window.orderBy)
+		if err != nil {
+			return
+		}
+		over = append(over, fmt.Sprintf("ORDER BY %s", sort))
+	}
+
+	query = fmt.Sprintf("%s(%s) OVER (%s)",
+		w.function.function, strings.Join(args, ", "), strings.Join(over, " "),
+	)
+	return
+}
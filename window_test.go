@@ -0,0 +1,54 @@
+package goutils
+
+import (
+	"testing"
+)
+
+func TestWindowFunction(t *testing.T) {
+	tests := []struct {
+		name     string
+		function windowFunction
+		window   *windowOption
+		expected string
+	}{
+		{"row number", RowNumber(), NewWindow().PartitionBy("o.user_id").OrderBy(DirDesc, "o.created_at"), `ROW_NUMBER() OVER (PARTITION BY "o"."user_id" ORDER BY "o"."created_at" DESC)`},
+		{"rank", Rank(), NewWindow().OrderBy(DirDesc, "o.amount"), `RANK() OVER (ORDER BY "o"."amount" DESC)`},
+		{"dense rank", DenseRank(), NewWindow().PartitionBy("o.user_id", "o.status").OrderBy(DirAsc, "o.amount"), `DENSE_RANK() OVER (PARTITION BY "o"."user_id", "o"."status" ORDER BY "o"."amount" ASC)`},
+		{"lag", Lag("o.amount", 1), NewWindow().OrderBy(DirAsc, "o.id"), `LAG("o"."amount", 1) OVER (ORDER BY "o"."id" ASC)`},
+		{"lead", Lead("o.amount", 2), NewWindow().PartitionBy("o.user_id").OrderBy(DirAsc, "o.id"), `LEAD("o"."amount", 2) OVER (PARTITION BY "o"."user_id" ORDER BY "o"."id" ASC)`},
+		{"running sum", RunningSum("o.amount"), NewWindow().PartitionBy("o.user_id").OrderBy(DirAsc, "o.created_at", "o.id"), `SUM("o"."amount") OVER (PARTITION BY "o"."user_id" ORDER BY "o"."created_at" ASC, "o"."id" ASC)`},
+		{"running count", RunningCount("o.id"), nil, `COUNT("o"."id") OVER ()`},
+	}
+
+	for _, test := range tests {
+		query := NewQueryBuilder(WithDialect(PostgreSQL))
+		query.AddSelection("o.id")
+		query.AddWindowFunction(test.function, test.window, "value")
+
+		res, values, err := query.GetQuery("orders", "o")
+		assertQuery(t, test.name, res, values, err, `SELECT "o"."id", `+test.expected+` "value" FROM "orders" "o"`, nil)
+	}
+}
+
+func TestWindowFunctionWithPagination(t *testing.T) {
+	window := NewWindow().PartitionBy("user_id").OrderBy(DirDesc, "amount")
+
+	query := NewQueryBuilder(WithDialect(MySQL))
+	query.AddSelection("id")
+	query.AddWindowFunction(RowNumber(), window, "rank")
+	query.AddWhere("status", "", "paid")
+	query.AddSort(DirAsc, "id")
+	query.AddPagination(NewPagination(2, 10))
+
+	// window is copied, so it can be changed after it is added
+	window.PartitionBy("status")
+
+	res, values, err := query.GetQuery("orders", "")
+	assertQuery(t, "pagination", res, values, err,
+		"SELECT `id`, ROW_NUMBER() OVER (PARTITION BY `user_id` ORDER BY `amount` DESC) `rank` FROM `orders` WHERE `status` = ? ORDER BY `id` ASC LIMIT 10 OFFSET 10",
+		[]interface{}{"paid"},
+	)
+
+	res, values, err = query.GetCountQuery("orders", "")
+	assertQuery(t, "count", res, values, err, "SELECT COUNT(*) FROM `orders` WHERE `status` = ?", []interface{}{"paid"})
+}